@@ -0,0 +1,272 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+
+	"github.com/kopoli/fretnoter/fretboard"
+	"github.com/kopoli/fretnoter/synth"
+	"github.com/kopoli/fretnoter/theory"
+)
+
+// How the notes are played
+const (
+	StyleUpDown = "up-down"
+	StyleStrum  = "strum"
+	StyleBlock  = "block"
+)
+
+const (
+	// Length of a single note when playing up and down in seconds
+	noteLength = 0.4
+
+	// Length of a chord in seconds
+	chordLength = 2.5
+
+	// Delay between the strings when strumming in seconds
+	strumDelay = 0.04
+)
+
+// Commands that can play WAV files
+var audioPlayers = []string{"paplay", "aplay", "afplay", "play"}
+
+// Get the pitches of the scale one octave up from the root that is nearest
+// to the lowest string
+func scalePitches(tuning []string, notes []string) ([]int, error) {
+	open, err := fretboard.StringPitches(tuning)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []int
+	prev := int(open[0]) - 1
+	for i := range notes {
+		pos, err := theory.NotePosition(notes[i])
+		if err != nil {
+			return nil, err
+		}
+		// Pitch 33 is A1, which is position 0 in theory.Notes
+		pitch := 33 + pos
+		for pitch <= prev {
+			pitch += len(theory.Notes)
+		}
+		ret = append(ret, pitch)
+		prev = pitch
+	}
+	return append(ret, ret[0]+len(theory.Notes)), nil
+}
+
+// Get the pitches of the sounding notes on the board
+func boardPitches(fb *fretboard.FretBoard) ([]int, error) {
+	var ret []int
+	for _, n := range fb.Notes {
+		if n.Name == "x" {
+			continue
+		}
+		ret = append(ret, int(n.Pitch))
+	}
+	sort.Ints(ret)
+	return ret, nil
+}
+
+// Get the pitches of the easiest voicing of the chord. The bass note of a
+// slash chord is the lowest if it is given.
+func chordPitches(tuning []string, root, chord, bass string) ([]int, error) {
+	notes, err := theory.GetChord(root, chord)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := fretboard.DefaultVoicingOptions
+	opts.MaxMuted = len(tuning)
+	opts.Bass = bass
+	voicings, err := fretboard.FindVoicings(tuning, notes, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(voicings) == 0 {
+		// Stack the chord from the root nearest to the lowest string if it
+		// can't be played
+		low, err := scalePitches(tuning, notes[:1])
+		if err != nil {
+			return nil, err
+		}
+		pitches, err := theory.GetChordPitches(theory.Pitch(low[0]), chord)
+		if err != nil {
+			return nil, err
+		}
+		ret := make([]int, len(pitches))
+		for i := range pitches {
+			ret[i] = int(pitches[i])
+		}
+		if bass != "" && !theory.SameNote(bass, notes[0]) {
+			pos, err := theory.NotePosition(bass)
+			if err != nil {
+				return nil, err
+			}
+			low := pitches[0] - 1
+			for low.Position() != pos {
+				low--
+			}
+			ret = append([]int{int(low)}, ret...)
+		}
+		return ret, nil
+	}
+
+	fb := fretboard.FretBoard{
+		Strings: len(tuning),
+		Tuning:  tuning,
+	}
+	err = fb.SetVoicing(voicings[0], notes)
+	if err != nil {
+		return nil, err
+	}
+	return boardPitches(&fb)
+}
+
+// Get the events that play the pitches in the given style
+func styleEvents(pitches []int, style string) ([]synth.Event, error) {
+	var ret []synth.Event
+	switch style {
+	case StyleUpDown:
+		for i := 0; i < len(pitches)*2-1; i++ {
+			p := i
+			if i >= len(pitches) {
+				p = len(pitches)*2 - 2 - i
+			}
+			ret = append(ret, synth.Event{
+				Note:     pitches[p],
+				Start:    float64(i) * noteLength,
+				Duration: noteLength * 2,
+				Velocity: 0.8,
+			})
+		}
+	case StyleStrum, StyleBlock:
+		delay := 0.0
+		if style == StyleStrum {
+			delay = strumDelay
+		}
+		for i := range pitches {
+			ret = append(ret, synth.Event{
+				Note:     pitches[i],
+				Start:    float64(i) * delay,
+				Duration: chordLength,
+				Velocity: 0.6,
+			})
+		}
+	default:
+		return nil, fmt.Errorf("unknown play style '%s', use %s, %s or %s",
+			style, StyleUpDown, StyleStrum, StyleBlock)
+	}
+	return ret, nil
+}
+
+// Get the sound of the chords of the boards strummed in order
+func progressionEvents(boards []fretboard.FretBoard) ([]synth.Event, error) {
+	var ret []synth.Event
+	for i := range boards {
+		pitches, err := boardPitches(&boards[i])
+		if err != nil {
+			return nil, err
+		}
+		events, err := styleEvents(pitches, StyleStrum)
+		if err != nil {
+			return nil, err
+		}
+		for j := range events {
+			events[j].Start += float64(i) * chordLength
+		}
+		ret = append(ret, events...)
+	}
+	return ret, nil
+}
+
+// Get the default play style of the board type
+func defaultStyle(boardType string) string {
+	if boardType == TypeScale {
+		return StyleUpDown
+	}
+	return StyleStrum
+}
+
+// Get the sound of a scale or chord played on the tuning
+func scaleChordEvents(tuning []string, root, scale, bass string, isScale bool, style string) ([]synth.Event, error) {
+	var pitches []int
+	if isScale {
+		notes, err := theory.GetScale(root, scale)
+		if err != nil {
+			return nil, err
+		}
+		pitches, err = scalePitches(tuning, notes)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		pitches, err = chordPitches(tuning, root, scale, bass)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return styleEvents(pitches, style)
+}
+
+func writeWAVFile(path string, events []synth.Event) error {
+	fp, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = synth.WriteWAV(fp, synth.Render(events))
+	if err != nil {
+		fp.Close()
+		return err
+	}
+	return fp.Close()
+}
+
+// Play the events with the first audio player that is found. The WAV file
+// is written to a new temporary file that is removed when the player exits.
+// Returns the WAV file path even if no player is found.
+func playEvents(events []synth.Event, log io.Writer) (string, error) {
+	fp, err := os.CreateTemp("", "fretnoter-*.wav")
+	if err != nil {
+		return "", err
+	}
+	path := fp.Name()
+	err = synth.WriteWAV(fp, synth.Render(events))
+	if err == nil {
+		err = fp.Close()
+	} else {
+		fp.Close()
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	for _, player := range audioPlayers {
+		cmd, err := exec.LookPath(player)
+		if err != nil {
+			continue
+		}
+		c := exec.Command(cmd, path)
+		err = c.Start()
+		if err != nil {
+			os.Remove(path)
+			return "", err
+		}
+		go func() {
+			err := c.Wait()
+			if err != nil {
+				fmt.Fprintf(log, "Playing with %s failed: %v\n", player, err)
+			}
+			os.Remove(path)
+		}()
+		return path, nil
+	}
+	return path, fmt.Errorf("no audio player found, wrote %s", path)
+}
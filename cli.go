@@ -0,0 +1,878 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kopoli/fretnoter/fretboard"
+	"github.com/kopoli/fretnoter/midi"
+	"github.com/kopoli/fretnoter/theory"
+)
+
+const defaultTuning = "EADGBE"
+
+type cliCommand struct {
+	name  string
+	args  string
+	help  string
+	flags func(fs *flag.FlagSet)
+	run   func(out io.Writer, fs *flag.FlagSet, args []string) error
+}
+
+var commands []cliCommand
+
+func init() {
+	commands = []cliCommand{
+		{
+			name:  "scale",
+			args:  "ROOT SCALE",
+			help:  "Print the notes and fretboard of a scale",
+			flags: boardFlags,
+			run:   cmdScaleChord(true),
+		},
+		{
+			name:  "chord",
+			args:  "ROOT CHORD|SYMBOL",
+			help:  "Print the notes and fretboard of a chord, e.g. C 7#9 or F#m7b5",
+			flags: boardFlags,
+			run:   cmdScaleChord(false),
+		},
+		{
+			name:  "overlay",
+			args:  "ROOT SCALE ROOT CHORD",
+			help:  "Print a chord drawn over a scale, the notes outside the scale highlighted",
+			flags: overlayFlags,
+			run:   cmdOverlay,
+		},
+		{
+			name:  "voicings",
+			args:  "ROOT CHORD|SYMBOL",
+			help:  "Print playable voicings of a chord, easiest first",
+			flags: voicingFlags,
+			run:   cmdVoicings,
+		},
+		{
+			name:  "identify",
+			args:  "[NOTES...]",
+			help:  "Identify the chord of the notes from the lowest or the fretted shape",
+			flags: identifyFlags,
+			run:   cmdIdentify,
+		},
+		{
+			name: "find-scales",
+			args: "NOTES...",
+			help: "Find the scales that contain the notes",
+			run:  cmdFindScales,
+		},
+		{
+			name:  "chords-in-scale",
+			args:  "ROOT SCALE",
+			help:  "Print the chords that can be played with the notes of a scale",
+			flags: chordListFlags,
+			run:   cmdChordsInScale,
+		},
+		{
+			name:  "progression",
+			args:  "ROOT SCALE NUMERALS...",
+			help:  "Print the chords and voicings of a progression of Roman numerals, e.g. I vi IV V",
+			flags: progressionFlags,
+			run:   cmdProgression,
+		},
+		{
+			name: "transpose",
+			args: "STEPS|KEY",
+			help: "Transpose all boards of the saved workspace by semitones or to a key",
+			run:  cmdTranspose,
+		},
+		{
+			name: "scales",
+			help: "List the known scales",
+			run:  cmdList(theory.Scales, nil),
+		},
+		{
+			name: "chords",
+			help: "List the known chords",
+			run:  cmdList(theory.Chords, theory.Aliases),
+		},
+		{
+			name: "tunings",
+			help: "List the preset instrument tunings",
+			run:  cmdTunings,
+		},
+		{
+			name: "help",
+			help: "Print this help",
+			run:  cmdHelp,
+		},
+	}
+}
+
+func boardFlags(fs *flag.FlagSet) {
+	fs.String("tuning", defaultTuning, "Tuning of the strings from the lowest or a preset name")
+	fs.Int("start-fret", 0, "First shown fret")
+	fs.Int("frets", defaultFrets, "Number of shown frets after the first")
+	fs.Int("capo", 0, "Fret of the capo")
+	fs.String("labels", "names", "Label the notes by their names, intervals, degrees or shapes")
+	fs.Bool("horizontal", false, "Draw the lowest string at the bottom and the frets from left to right")
+	fs.Bool("left-handed", false, "Draw the board mirrored for left-handed players")
+	fs.String("positions", "", "Grey the scale notes outside a position: caged or 3nps")
+	fs.Int("position", 1, "The shown position from the lowest")
+	fs.Int("inversion", 0, "Play the chord in an inversion, 1 for the second chord note in the bass")
+	fs.String("output", "", "Write the board to a .svg, .png, .wav or .mid file instead")
+	fs.String("style", "", "How the notes are played to a .wav file: up-down, strum or block")
+	midiFlags(fs)
+}
+
+func overlayFlags(fs *flag.FlagSet) {
+	fs.String("tuning", defaultTuning, "Tuning of the strings from the lowest or a preset name")
+	fs.Int("start-fret", 0, "First shown fret")
+	fs.Int("frets", defaultFrets, "Number of shown frets after the first")
+	fs.Int("capo", 0, "Fret of the capo")
+	fs.String("labels", "names", "Label the notes by their names, intervals, degrees or shapes")
+	fs.Bool("horizontal", false, "Draw the lowest string at the bottom and the frets from left to right")
+	fs.Bool("left-handed", false, "Draw the board mirrored for left-handed players")
+	fs.Bool("base-chord", false, "The first of the pairs is a chord instead of a scale")
+	fs.Bool("overlay-scale", false, "The second of the pairs is a scale instead of a chord")
+	fs.String("output", "", "Write the board to a .svg or .png file instead")
+}
+
+func midiFlags(fs *flag.FlagSet) {
+	fs.Int("tempo", defaultTempo, "Quarter notes per minute in a .mid file")
+	fs.String("note-length", defaultNoteLength, "Length of the notes in a .mid file, e.g. half or eighth")
+}
+
+func chordListFlags(fs *flag.FlagSet) {
+	fs.String("tuning", defaultTuning, "Tuning of the strings from the lowest or a preset name")
+	fs.Int("capo", 0, "Fret of the capo")
+	fs.String("output", "", "Write the chords to a .mid file instead")
+	midiFlags(fs)
+}
+
+// parseArgs parses the flags that may also be given after the positional
+// arguments and returns the positional arguments
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var ret []string
+	for {
+		err := fs.Parse(args)
+		if err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return ret, nil
+		}
+		ret = append(ret, args[0])
+		args = args[1:]
+	}
+}
+
+func progressionFlags(fs *flag.FlagSet) {
+	fs.String("tuning", defaultTuning, "Tuning of the strings from the lowest or a preset name")
+	fs.Int("capo", 0, "Fret of the capo")
+	fs.String("output", "", "Write the chords to a .mid file instead")
+	midiFlags(fs)
+}
+
+func voicingFlags(fs *flag.FlagSet) {
+	opts := fretboard.DefaultVoicingOptions
+	fs.String("tuning", defaultTuning, "Tuning of the strings from the lowest or a preset name")
+	fs.Int("max-span", opts.MaxSpan, "Maximum distance between fretted notes")
+	fs.Int("fingers", opts.Fingers, "Maximum number of fretting fingers")
+	fs.Bool("root-bass", opts.RootInBass, "Require the root on the lowest played string")
+	fs.Int("max-muted", opts.MaxMuted, "Maximum number of muted strings")
+	fs.Int("max-fret", opts.MaxFret, "Highest fret to search from above the capo")
+	fs.Int("capo", 0, "Fret of the capo")
+	fs.Int("inversion", 0, "Play the chord in an inversion, 1 for the second chord note in the bass")
+	fs.Int("count", 10, "Number of voicings to print, 0 for all")
+}
+
+func identifyFlags(fs *flag.FlagSet) {
+	fs.String("tuning", defaultTuning, "Tuning of the strings from the lowest or a preset name")
+	fs.String("frets", "", "Fretted shape from the lowest string, e.g. x32010")
+}
+
+// Get the tuning of the strings or a preset tuning by its name, e.g.
+// "Guitar: Drop D"
+func getFlagTuning(fs *flag.FlagSet) ([]string, error) {
+	tuning := fs.Lookup("tuning").Value.String()
+	if t, ok := fretboard.FindPreset(tuning); ok {
+		tuning = t.Notes
+	}
+	return parseTuning(tuning)
+}
+
+func getFlagInt(fs *flag.FlagSet, name string) int {
+	return fs.Lookup(name).Value.(flag.Getter).Get().(int)
+}
+
+func getFlagBool(fs *flag.FlagSet, name string) bool {
+	return fs.Lookup(name).Value.(flag.Getter).Get().(bool)
+}
+
+func getFlagFrets(fs *flag.FlagSet) (int, int, error) {
+	start := getFlagInt(fs, "start-fret")
+	frets := getFlagInt(fs, "frets")
+	if start < 0 || start > maxFrets {
+		return 0, 0, fmt.Errorf("starting fret must be between 0 and %d", maxFrets)
+	}
+	if frets < 1 || frets > maxFrets {
+		return 0, 0, fmt.Errorf("number of frets must be between 1 and %d", maxFrets)
+	}
+	return start, frets, nil
+}
+
+func getFlagCapo(fs *flag.FlagSet) (int, error) {
+	capo := getFlagInt(fs, "capo")
+	if capo < 0 || capo > maxCapo {
+		return 0, fmt.Errorf("capo must be between 0 and %d", maxCapo)
+	}
+	return capo, nil
+}
+
+// Get the root and the chord of the arguments. The chord may be given as a
+// single chord symbol, e.g. "F#m7b5" or "D/F#", or as the root and a chord
+// name or the suffix of a symbol, e.g. "C" "7#9". Returns also the bass
+// note of a slash chord.
+func chordArgs(args []string) ([]string, string, error) {
+	switch len(args) {
+	case 1:
+		root, chord, bass, err := symbolChord(args[0])
+		if err != nil {
+			return nil, "", err
+		}
+		return []string{root, chord}, bass, nil
+	case 2:
+		if _, ok := theory.ChordName(args[1]); ok {
+			return args, "", nil
+		}
+		if _, err := theory.NormalizeNote(args[0]); err != nil {
+			return nil, "", err
+		}
+		root, chord, bass, err := symbolChord(strings.ToUpper(args[0][:1]) + args[0][1:] + args[1])
+		if err != nil {
+			return nil, "", &theory.UnknownChordError{Chord: args[1]}
+		}
+		return []string{root, chord}, bass, nil
+	}
+	return nil, "", fmt.Errorf("expected a chord symbol or 2 arguments, got %d arguments", len(args))
+}
+
+// Get the bass note of the inversion given with --inversion or the bass
+// note of the slash chord
+func getFlagInversion(fs *flag.FlagSet, root, chord, bass string) (string, error) {
+	inv := getFlagInt(fs, "inversion")
+	if inv == 0 {
+		return bass, nil
+	}
+	if bass != "" {
+		return "", fmt.Errorf("give either a slash chord or --inversion, not both")
+	}
+	return theory.Inversion(root, chord, inv)
+}
+
+func cmdScaleChord(isScale bool) func(io.Writer, *flag.FlagSet, []string) error {
+	return func(out io.Writer, fs *flag.FlagSet, args []string) error {
+		var bass string
+		if isScale && len(args) != 2 {
+			return fmt.Errorf("expected 2 arguments, got %d", len(args))
+		} else if !isScale {
+			var err error
+			args, bass, err = chordArgs(args)
+			if err != nil {
+				return err
+			}
+		}
+		tuning, err := getFlagTuning(fs)
+		if err != nil {
+			return err
+		}
+		start, frets, err := getFlagFrets(fs)
+		if err != nil {
+			return err
+		}
+		capo, err := getFlagCapo(fs)
+		if err != nil {
+			return err
+		}
+		if isScale && getFlagInt(fs, "inversion") != 0 {
+			return fmt.Errorf("only chords have inversions")
+		} else if !isScale {
+			bass, err = getFlagInversion(fs, args[0], args[1], bass)
+			if err != nil {
+				return err
+			}
+		}
+		fb, err := addBoard(tuning, args[0], args[1], bass, isScale, start, frets, capo)
+		if err != nil {
+			return err
+		}
+		fb.Labels = labelMode(fs.Lookup("labels").Value.String())
+		fb.Horizontal = getFlagBool(fs, "horizontal")
+		fb.LeftHanded = getFlagBool(fs, "left-handed")
+		if name := fs.Lookup("positions").Value.String(); name != "" {
+			system := positionSystem(name)
+			if system == fretboard.PositionsNone {
+				return fmt.Errorf("unknown position system '%s', use caged or 3nps", name)
+			}
+			if !isScale {
+				return fmt.Errorf("positions can only be shown on scales")
+			}
+			pos := getFlagInt(fs, "position")
+			positions, err := setScalePosition(fb, args[0], args[1], system, pos-1)
+			if err != nil {
+				return err
+			}
+			if pos < 1 || pos > len(positions) {
+				return fmt.Errorf("position must be between 1 and %d", len(positions))
+			}
+		}
+		if path := fs.Lookup("output").Value.String(); path != "" {
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".wav":
+				err = writeScaleChordWAV(path, tuning, args[0], args[1], bass, isScale,
+					fs.Lookup("style").Value.String())
+			case ".mid":
+				var mf *midi.File
+				mf, err = scaleChordMIDI(tuning, args[0], args[1], bass, isScale,
+					getFlagInt(fs, "tempo"), fs.Lookup("note-length").Value.String())
+				if err == nil {
+					err = writeMIDIFile(path, mf)
+				}
+			default:
+				err = exportBoard(path, fb.Name, fb)
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out, "Wrote", path)
+			return nil
+		}
+		fmt.Fprintln(out, fb.Name)
+		fmt.Fprintln(out)
+		WriteASCIIBoard(out, fb)
+		return nil
+	}
+}
+
+func cmdOverlay(out io.Writer, fs *flag.FlagSet, args []string) error {
+	if len(args) != 4 {
+		return fmt.Errorf("expected 4 arguments, got %d", len(args))
+	}
+	tuning, err := getFlagTuning(fs)
+	if err != nil {
+		return err
+	}
+	start, frets, err := getFlagFrets(fs)
+	if err != nil {
+		return err
+	}
+	capo, err := getFlagCapo(fs)
+	if err != nil {
+		return err
+	}
+
+	base := OverlayLayer{Root: args[0], Name: args[1], IsScale: !getFlagBool(fs, "base-chord")}
+	overlay := OverlayLayer{Root: args[2], Name: args[3], IsScale: getFlagBool(fs, "overlay-scale")}
+	fb, err := addOverlayBoard(tuning, base, overlay, start, frets, capo)
+	if err != nil {
+		return err
+	}
+	fb.Labels = labelMode(fs.Lookup("labels").Value.String())
+	fb.Horizontal = getFlagBool(fs, "horizontal")
+	fb.LeftHanded = getFlagBool(fs, "left-handed")
+
+	if path := fs.Lookup("output").Value.String(); path != "" {
+		err = exportBoard(path, fb.Name, fb)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "Wrote", path)
+		return nil
+	}
+	fmt.Fprintln(out, fb.Name)
+	fmt.Fprintln(out)
+	WriteASCIIBoard(out, fb)
+	return nil
+}
+
+func writeScaleChordWAV(path string, tuning []string, root, scale, bass string, isScale bool, style string) error {
+	if style == "" {
+		style = StyleUpDown
+		if !isScale {
+			style = StyleStrum
+		}
+	}
+	events, err := scaleChordEvents(tuning, root, scale, bass, isScale, style)
+	if err != nil {
+		return err
+	}
+	return writeWAVFile(path, events)
+}
+
+func cmdVoicings(out io.Writer, fs *flag.FlagSet, args []string) error {
+	args, bass, err := chordArgs(args)
+	if err != nil {
+		return err
+	}
+	tuning, err := getFlagTuning(fs)
+	if err != nil {
+		return err
+	}
+	notes, err := theory.GetChord(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	capo, err := getFlagCapo(fs)
+	if err != nil {
+		return err
+	}
+	bass, err = getFlagInversion(fs, args[0], args[1], bass)
+	if err != nil {
+		return err
+	}
+	shown := notes
+	if bass != "" {
+		shown, err = theory.GetSlashChord(args[0], args[1], bass)
+		if err != nil {
+			return err
+		}
+	}
+
+	opts := fretboard.VoicingOptions{
+		MaxSpan:    getFlagInt(fs, "max-span"),
+		Fingers:    getFlagInt(fs, "fingers"),
+		RootInBass: getFlagBool(fs, "root-bass"),
+		MaxMuted:   getFlagInt(fs, "max-muted"),
+		MaxFret:    getFlagInt(fs, "max-fret"),
+		Capo:       capo,
+		Bass:       bass,
+	}
+	voicings, err := fretboard.FindVoicings(tuning, notes, opts)
+	if err != nil {
+		return err
+	}
+
+	count := getFlagInt(fs, "count")
+	if count <= 0 || count > len(voicings) {
+		count = len(voicings)
+	}
+
+	fmt.Fprintf(out, "%s %s%s voicings\nTuning: %s\nNotes: %s%s\n\n", notes[0], args[1],
+		slashName(bass), formatTuning(tuning), strings.Join(shown, " "),
+		capoTitle(notes[0], args[1], capo))
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(out, "%3d. %-20s score %d\n", i+1, voicings[i], voicings[i].Score)
+	}
+	return nil
+}
+
+func cmdIdentify(out io.Writer, fs *flag.FlagSet, args []string) error {
+	notes := args
+	if shape := fs.Lookup("frets").Value.String(); shape != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("give either notes or --frets, not both")
+		}
+		tuning, err := getFlagTuning(fs)
+		if err != nil {
+			return err
+		}
+		v, err := fretboard.ParseVoicing(shape)
+		if err != nil {
+			return err
+		}
+		notes, err = v.Notes(tuning)
+		if err != nil {
+			return err
+		}
+	}
+	if len(notes) == 0 {
+		return fmt.Errorf("no notes given")
+	}
+
+	matches, err := theory.IdentifyChord(notes)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("no chord found for notes: %s", strings.Join(notes, " "))
+	}
+
+	fmt.Fprintf(out, "Notes: %s\n\n", strings.Join(notes, " "))
+	for i := range matches {
+		line := fmt.Sprintf("%3d. %s", i+1, matches[i].Name())
+		if len(matches[i].Missing) > 0 {
+			line = fmt.Sprintf("%-25s (no %s)", line, strings.Join(matches[i].Missing, " "))
+		}
+		fmt.Fprintln(out, line)
+	}
+	return nil
+}
+
+func cmdFindScales(out io.Writer, fs *flag.FlagSet, args []string) error {
+	notes, err := parseNotes(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	matches, err := theory.FindScales(notes)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Notes: %s\n\nExact matches:\n", strings.Join(notes, " "))
+	if len(matches) == 0 || !matches[0].Exact {
+		fmt.Fprintf(out, "  -\n")
+	}
+	exact := true
+	for _, m := range matches {
+		if exact && !m.Exact {
+			exact = false
+			fmt.Fprintf(out, "\nScales with more notes:\n")
+		}
+		if m.Exact {
+			fmt.Fprintf(out, "  %s\n", m.Name())
+		} else {
+			fmt.Fprintf(out, "  %-35s +%s\n", m.Name(), strings.Join(m.Extra, " "))
+		}
+	}
+	return nil
+}
+
+func cmdChordsInScale(out io.Writer, fs *flag.FlagSet, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected 2 arguments, got %d", len(args))
+	}
+	tuning, err := getFlagTuning(fs)
+	if err != nil {
+		return err
+	}
+	capo, err := getFlagCapo(fs)
+	if err != nil {
+		return err
+	}
+	ib, err := addChordListBoard(tuning, args[0], args[1], capo)
+	if err != nil {
+		return err
+	}
+
+	if path := fs.Lookup("output").Value.String(); path != "" {
+		mf, err := chordListMIDI(ib, getFlagInt(fs, "tempo"),
+			fs.Lookup("note-length").Value.String())
+		if err != nil {
+			return err
+		}
+		err = writeMIDIFile(path, mf)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "Wrote", path)
+		return nil
+	}
+
+	fmt.Fprintf(out, "%s %s chords\nNotes: %s%s\n\n", args[0], args[1],
+		strings.Join(ib.ScaleNotes, " "), capoTitle(ib.ScaleNotes[0], args[1], capo))
+	for _, note := range ib.ScaleNotes {
+		heading := note
+		if capo > 0 {
+			shape, err := theory.TransposeNote(note, -capo)
+			if err != nil {
+				return err
+			}
+			heading = fmt.Sprintf("%s (%s shape)", note, shape)
+		}
+		fmt.Fprintf(out, "%-3s %s\n", heading+":", strings.Join(ib.Chords[note], ", "))
+	}
+	return nil
+}
+
+func cmdProgression(out io.Writer, fs *flag.FlagSet, args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("expected at least 3 arguments, got %d", len(args))
+	}
+	tuning, err := getFlagTuning(fs)
+	if err != nil {
+		return err
+	}
+	capo, err := getFlagCapo(fs)
+	if err != nil {
+		return err
+	}
+	opts := fretboard.DefaultVoicingOptions
+	opts.Capo = capo
+	ib, err := addProgressionBoard(tuning, args[0], args[1], strings.Join(args[2:], "-"), opts)
+	if err != nil {
+		return err
+	}
+
+	if path := fs.Lookup("output").Value.String(); path != "" {
+		mf, err := progressionMIDI(ib, getFlagInt(fs, "tempo"),
+			fs.Lookup("note-length").Value.String())
+		if err != nil {
+			return err
+		}
+		err = writeMIDIFile(path, mf)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "Wrote", path)
+		return nil
+	}
+
+	fmt.Fprintf(out, "%s\n\n", ib.Name)
+	for i, c := range ib.Progression {
+		fmt.Fprintf(out, "%-8s %-15s %s\n", c.Numeral, c.Name(), ib.Voicings[i])
+	}
+	return nil
+}
+
+func cmdTranspose(out io.Writer, fs *flag.FlagSet, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected 1 argument, got %d", len(args))
+	}
+	ss, err := Load()
+	if err != nil {
+		return err
+	}
+	steps, key, err := transposeSteps(ss.Root, args[0])
+	if err != nil {
+		return err
+	}
+	err = transposeWorkspace(ss, steps, key)
+	if err != nil {
+		return err
+	}
+	err = Save(ss)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Transposed %d boards by %d semitones to %s\n", len(ss.Boards), steps, ss.Root)
+	return nil
+}
+
+// List the names of the map. The aliases of the names are printed after
+// them if aliases is given.
+func cmdList(m map[string][]int, aliases func(string) []string) func(io.Writer, *flag.FlagSet, []string) error {
+	return func(out io.Writer, fs *flag.FlagSet, args []string) error {
+		names := make([]string, 0, len(m))
+		for k := range m {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for i := range names {
+			if aliases == nil || len(aliases(names[i])) == 0 {
+				fmt.Fprintln(out, names[i])
+				continue
+			}
+			fmt.Fprintf(out, "%-12s also %s\n", names[i], strings.Join(aliases(names[i]), ", "))
+		}
+		return nil
+	}
+}
+
+func cmdTunings(out io.Writer, fs *flag.FlagSet, args []string) error {
+	for _, ins := range fretboard.Instruments {
+		for _, t := range ins.Tunings {
+			fmt.Fprintf(out, "%-35s %s\n", fretboard.PresetName(ins, t), t.Notes)
+		}
+	}
+	return nil
+}
+
+func cmdHelp(out io.Writer, fs *flag.FlagSet, args []string) error {
+	fmt.Fprintf(out, "Usage: fretnoter [COMMAND] [ARGS]\n\n")
+	fmt.Fprintf(out, "Starts the GUI if no command is given.\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(out, "  %-30s %s\n", strings.TrimSpace(c.name+" "+c.args), c.help)
+		if c.flags != nil {
+			fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+			c.flags(fs)
+			fs.VisitAll(func(f *flag.Flag) {
+				fmt.Fprintf(out, "      --%-24s %s (default %q)\n", f.Name, f.Usage, f.DefValue)
+			})
+		}
+	}
+	return nil
+}
+
+// CLIMain runs the command given in args and prints its output to out
+func CLIMain(out io.Writer, args []string) error {
+	err := LoadDefinitions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: invalid definitions: %v\n", err)
+	}
+
+	for _, c := range commands {
+		if c.name != args[0] {
+			continue
+		}
+
+		fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+		fs.SetOutput(out)
+		if c.flags != nil {
+			c.flags(fs)
+		}
+		pos, err := parseArgs(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		return c.run(out, fs, pos)
+	}
+	return fmt.Errorf("unknown command '%s', see 'fretnoter help'", args[0])
+}
+
+// WriteASCIIBoard prints the fretboard as in the GUI. Root notes are
+// marked with brackets, grey notes with parentheses, highlighted notes with
+// angle brackets and bass notes with braces.
+func WriteASCIIBoard(out io.Writer, fb *fretboard.FretBoard) {
+	// The strings are drawn with "|" on vertical and "-" on horizontal boards
+	empty := "|"
+	if fb.Horizontal {
+		empty = "-"
+	}
+
+	// The strings are left out below their starting frets
+	starts := fb.StringStarts()
+	grid := make([][]string, fb.Frets+1)
+	for i := range grid {
+		grid[i] = make([]string, fb.Strings)
+		if i == 0 {
+			continue
+		}
+		for s := range grid[i] {
+			if i+fb.StartingFret > starts[s] {
+				grid[i][s] = empty
+			}
+		}
+	}
+	for _, note := range fb.Notes {
+		fr := note.Fret - fb.StartingFret
+		if fr < 0 || fr >= len(grid) || note.String >= fb.Strings {
+			continue
+		}
+		name := note.Label(fb.Labels)
+		switch note.Type {
+		case fretboard.NoteRoot:
+			name = "[" + name + "]"
+		case fretboard.NoteGrey:
+			name = "(" + name + ")"
+		case fretboard.NoteHighlight:
+			name = "<" + name + ">"
+		case fretboard.NoteBass:
+			name = "{" + name + "}"
+		case fretboard.NoteUnvoiced:
+			name = "x"
+		}
+		grid[fr][note.String] = name
+	}
+
+	// The cells are widened to fit the longest label, e.g. a tuning with a
+	// starting fret
+	cellw := 5
+	for _, label := range fb.Tuning {
+		if len(label)+1 > cellw {
+			cellw = len(label) + 1
+		}
+	}
+	for i := range grid {
+		for _, label := range grid[i] {
+			if len(label)+1 > cellw {
+				cellw = len(label) + 1
+			}
+		}
+	}
+	cell := func(s string) string {
+		pad := cellw - len(s)
+		return strings.Repeat(" ", pad/2) + s + strings.Repeat(" ", pad-pad/2)
+	}
+
+	// Only the nut is drawn with a double line
+	nut := "="
+	if fb.StartingFret > 0 {
+		nut = "-"
+	}
+
+	if fb.Horizontal {
+		writeHorizontalASCII(out, fb, grid, cell, nut)
+		return
+	}
+
+	strs := make([]int, fb.Strings)
+	for s := range strs {
+		strs[s] = s
+		if fb.LeftHanded {
+			strs[s] = fb.Strings - 1 - s
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("    ")
+	for _, s := range strs {
+		sb.WriteString(cell(fb.Tuning[s]))
+	}
+	fmt.Fprintln(out, strings.TrimRight(sb.String(), " "))
+
+	for i := range grid {
+		sb.Reset()
+		fmt.Fprintf(&sb, "%3d ", i+fb.StartingFret)
+		for _, s := range strs {
+			sb.WriteString(cell(grid[i][s]))
+		}
+		fmt.Fprintln(out, strings.TrimRight(sb.String(), " "))
+		if i == 0 {
+			fmt.Fprintf(out, "    %s\n", strings.Repeat(nut, cellw*fb.Strings))
+		}
+	}
+}
+
+// Print the board with the lowest string at the bottom and the frets from
+// left to right, or from right to left on a left-handed board
+func writeHorizontalASCII(out io.Writer, fb *fretboard.FretBoard, grid [][]string,
+	cell func(string) string, nut string) {
+
+	nut = strings.Repeat(nut, 2)
+
+	firstw := 4
+	for _, label := range fb.Tuning {
+		if len(label)+1 > firstw {
+			firstw = len(label) + 1
+		}
+	}
+
+	// Get a line with the first fret, the nut and the other frets
+	line := func(first string, cells []string) string {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "%-*s", firstw, first)
+		if fb.LeftHanded {
+			for i := len(cells) - 1; i > 0; i-- {
+				sb.WriteString(cell(cells[i]))
+			}
+			sb.WriteString(nut)
+			sb.WriteString(cell(cells[0]))
+		} else {
+			sb.WriteString(cell(cells[0]))
+			sb.WriteString(nut)
+			for i := 1; i < len(cells); i++ {
+				sb.WriteString(cell(cells[i]))
+			}
+		}
+		return strings.TrimRight(sb.String(), " ")
+	}
+
+	cells := make([]string, len(grid))
+	for i := range grid {
+		cells[i] = fmt.Sprintf("%d", i+fb.StartingFret)
+	}
+	fmt.Fprintln(out, line("", cells))
+
+	for s := fb.Strings - 1; s >= 0; s-- {
+		for i := range grid {
+			cells[i] = grid[i][s]
+		}
+		fmt.Fprintln(out, line(fb.Tuning[s], cells))
+	}
+}
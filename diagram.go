@@ -0,0 +1,372 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/aarzilli/nucular"
+	"github.com/aarzilli/nucular/command"
+	"github.com/aarzilli/nucular/font"
+	"github.com/aarzilli/nucular/rect"
+	"github.com/aarzilli/nucular/style"
+	"github.com/kopoli/fretnoter/fretboard"
+	"github.com/kopoli/fretnoter/theory"
+)
+
+// diagramCanvas is a surface where the fret diagrams are drawn
+type diagramCanvas interface {
+	FillRect(r rect.Rect, c color.RGBA)
+	StrokeLine(p0, p1 image.Point, thickness int, c color.RGBA)
+	FillCircle(r rect.Rect, c color.RGBA)
+	// Draw text with its top left corner at the rect position
+	DrawText(r rect.Rect, text string, c color.RGBA)
+
+	// Set the font size relative to the default font size
+	SetFontScaling(scaling float64)
+	TextWidth(text string) int
+	TextHeight() int
+}
+
+var (
+	white = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	black = color.RGBA{0, 0, 0, 0xff}
+	red   = color.RGBA{0xff, 0, 0, 0xff}
+	grey  = color.RGBA{0x80, 0x80, 0x80, 0xff}
+
+	lightGrey = color.RGBA{0xe0, 0xe0, 0xe0, 0xff}
+	magenta   = color.RGBA{0xc0, 0x30, 0xc0, 0xff}
+	teal      = color.RGBA{0x00, 0x90, 0x90, 0xff}
+)
+
+type noteColor struct {
+	back color.RGBA
+	fore color.RGBA
+}
+
+var circleColors = map[fretboard.NoteType]noteColor{
+	fretboard.NoteUnvoiced:  {white, black},
+	fretboard.NoteRoot:      {red, black},
+	fretboard.NoteBlack:     {black, white},
+	fretboard.NoteGrey:      {grey, white},
+	fretboard.NoteHighlight: {magenta, white},
+	fretboard.NoteBass:      {teal, white},
+}
+
+// Colours of the notes by their degree when labelled by intervals or
+// degrees. The other degrees are black.
+var functionColors = map[int]noteColor{
+	1: {red, black},
+	3: {color.RGBA{0x20, 0x90, 0x30, 0xff}, white},
+	5: {color.RGBA{0x20, 0x50, 0xc0, 0xff}, white},
+	7: {color.RGBA{0xe0, 0x80, 0x10, 0xff}, black},
+}
+
+// Get the colours of the note on the board
+func noteColors(fb *fretboard.FretBoard, note fretboard.Note) noteColor {
+	if (fb.Labels != fretboard.LabelIntervals && fb.Labels != fretboard.LabelDegrees) ||
+		note.Interval == "" ||
+		(note.Type != fretboard.NoteRoot && note.Type != fretboard.NoteBlack) {
+		return circleColors[note.Type]
+	}
+	if c, ok := functionColors[theory.IntervalDegree(note.Interval)]; ok {
+		return c
+	}
+	return circleColors[fretboard.NoteBlack]
+}
+
+// diagramLayout has the positions of the strings and frets of a diagram.
+// The positions are of a vertical right-handed board, which is then
+// transformed to the orientation of the board.
+type diagramLayout struct {
+	fb *fretboard.FretBoard
+
+	// The drawn area on the screen
+	bounds rect.Rect
+
+	borderX     int
+	boardShiftX int
+
+	// Position of the nut on the first string
+	x int
+	y int
+
+	fretwidth  int
+	fretheight int
+}
+
+func newDiagramLayout(bounds rect.Rect, fb *fretboard.FretBoard) diagramLayout {
+	// The layout is calculated with the origin at the top left corner
+	w, h := bounds.W, bounds.H
+	if fb.Horizontal {
+		w, h = h, w
+	}
+
+	borderX := w * 10 / 100
+	borderY := h * 5 / 100
+
+	boardBounds := rect.Rect{
+		X: borderX,
+		Y: borderY,
+		W: w - (borderX * 2),
+		H: h - (borderY * 2),
+	}
+
+	ret := diagramLayout{
+		fb:         fb,
+		bounds:     bounds,
+		borderX:    borderX,
+		fretwidth:  boardBounds.W / fb.Strings,
+		fretheight: boardBounds.H / (fb.Frets + 1),
+
+		// Shift the board to the right a bit so it isn't on top of the numbers
+		boardShiftX: w * 2 / 100,
+	}
+
+	ret.x = boardBounds.X + ret.fretwidth/2 + ret.boardShiftX
+	ret.y = boardBounds.Y + ret.fretheight
+
+	return ret
+}
+
+// toScreen transforms a point of the layout to the screen
+func (l diagramLayout) toScreen(p image.Point) image.Point {
+	b := l.bounds
+	switch {
+	case l.fb.Horizontal && l.fb.LeftHanded:
+		return image.Point{b.X + b.W - p.Y, b.Y + b.H - p.X}
+	case l.fb.Horizontal:
+		return image.Point{b.X + p.Y, b.Y + b.H - p.X}
+	case l.fb.LeftHanded:
+		return image.Point{b.X + b.W - p.X, b.Y + p.Y}
+	}
+	return image.Point{b.X + p.X, b.Y + p.Y}
+}
+
+// fromScreen transforms a point on the screen to the layout
+func (l diagramLayout) fromScreen(p image.Point) image.Point {
+	b := l.bounds
+	switch {
+	case l.fb.Horizontal && l.fb.LeftHanded:
+		return image.Point{b.Y + b.H - p.Y, b.X + b.W - p.X}
+	case l.fb.Horizontal:
+		return image.Point{b.Y + b.H - p.Y, p.X - b.X}
+	case l.fb.LeftHanded:
+		return image.Point{b.X + b.W - p.X, p.Y - b.Y}
+	}
+	return image.Point{p.X - b.X, p.Y - b.Y}
+}
+
+// cellAt returns the string and the fret at the given point on the screen
+func (l diagramLayout) cellAt(p image.Point) (int, int, bool) {
+	p = l.fromScreen(p)
+	if p.X < l.x-l.fretwidth/2 || p.Y < l.y-l.fretheight {
+		return 0, 0, false
+	}
+
+	str := (p.X - l.x + l.fretwidth/2) / l.fretwidth
+	row := (p.Y - l.y + l.fretheight) / l.fretheight
+	if str >= l.fb.Strings || row > l.fb.Frets {
+		return 0, 0, false
+	}
+	return str, row + l.fb.StartingFret, true
+}
+
+// orientedCanvas transforms the drawing of a vertical right-handed board
+// to the orientation of the board. The texts are not rotated.
+type orientedCanvas struct {
+	diagramCanvas
+	l diagramLayout
+}
+
+func (c *orientedCanvas) FillRect(r rect.Rect, col color.RGBA) {
+	p0 := c.l.toScreen(image.Point{r.X, r.Y})
+	p1 := c.l.toScreen(image.Point{r.X + r.W, r.Y + r.H})
+	c.diagramCanvas.FillRect(rect.FromRectangle(image.Rectangle{p0, p1}.Canon()), col)
+}
+
+func (c *orientedCanvas) StrokeLine(p0, p1 image.Point, thickness int, col color.RGBA) {
+	c.diagramCanvas.StrokeLine(c.l.toScreen(p0), c.l.toScreen(p1), thickness, col)
+}
+
+func (c *orientedCanvas) FillCircle(r rect.Rect, col color.RGBA) {
+	center := c.l.toScreen(image.Point{r.X + r.W/2, r.Y + r.H/2})
+	if c.l.fb.Horizontal {
+		r.W, r.H = r.H, r.W
+	}
+	c.diagramCanvas.FillCircle(rect.Rect{X: center.X - r.W/2, Y: center.Y - r.H/2, W: r.W, H: r.H}, col)
+}
+
+func (c *orientedCanvas) DrawText(r rect.Rect, text string, col color.RGBA) {
+	w, h := c.TextWidth(text), c.TextHeight()
+	center := c.l.toScreen(image.Point{r.X + w/2, r.Y + h/2})
+	c.diagramCanvas.DrawText(rect.Rect{X: center.X - w/2, Y: center.Y - h/2, W: w, H: h}, text, col)
+}
+
+func drawDiagram(canvas diagramCanvas, bounds rect.Rect, fb *fretboard.FretBoard) {
+	l := newDiagramLayout(bounds, fb)
+	out := &orientedCanvas{canvas, l}
+	x, y := l.x, l.y
+	fretwidth, fretheight := l.fretwidth, l.fretheight
+	borderX, boardShiftX := l.borderX, l.boardShiftX
+
+	circleW := fretheight
+	if fretheight > fretwidth {
+		circleW = fretwidth
+	}
+
+	// Get a font that is relatively scaled (the 12.0 is from Style.DefaultFont)
+	out.SetFontScaling((float64(circleW) * 0.4) / 12.0)
+
+	// Maximum width of the fret number box
+	fretnumWidth := out.TextWidth("00") + borderX/2
+
+	// Draw the background
+	canvas.FillRect(bounds, white)
+
+	// there is some rounding error between this and boardBounds.Max()
+	maxy := y + fretheight*(fb.Frets)
+
+	// Shade the frets below the capo
+	capo := fb.Capo - fb.StartingFret
+	shaded := capo - 1
+	if shaded > fb.Frets {
+		shaded = fb.Frets
+	}
+	if shaded > 0 {
+		out.FillRect(rect.Rect{
+			X: x,
+			Y: y,
+			W: fretwidth * (fb.Strings - 1),
+			H: fretheight * shaded,
+		}, lightGrey)
+	}
+
+	// Print fret grid. The strings are drawn from their starting frets.
+	starts, err := fretboard.StringStarts(fb.Tuning)
+	if err != nil {
+		starts = make([]int, fb.Strings)
+	}
+	for i := 0; i < fb.Strings; i++ {
+		first := starts[i] - fb.StartingFret
+		if first < 0 {
+			first = 0
+		} else if first > fb.Frets {
+			continue
+		}
+
+		xpos := x + fretwidth*i
+		start := image.Point{xpos, y + fretheight*first}
+		stop := image.Point{xpos, maxy}
+		out.StrokeLine(start, stop, 2, black)
+
+		// Mark the nut of a string that starts at a later fret
+		if starts[i] > fb.StartingFret {
+			out.StrokeLine(image.Point{xpos - fretwidth/4, start.Y},
+				image.Point{xpos + fretwidth/4, start.Y}, 6, black)
+		}
+	}
+	for i := 0; i < fb.Frets+1; i++ {
+		// Leave out the strings that start at a later fret
+		first, last := -1, -1
+		for str := range starts {
+			if starts[str] <= i+fb.StartingFret {
+				if first < 0 {
+					first = str
+				}
+				last = str
+			}
+		}
+		if first < 0 {
+			continue
+		}
+
+		ypos := y + fretheight*i
+		start := image.Point{x + fretwidth*first, ypos}
+		stop := image.Point{x + fretwidth*last, ypos}
+		out.StrokeLine(start, stop, 2, black)
+	}
+
+	// Print the capo
+	if capo > 0 && capo <= fb.Frets {
+		ypos := y + fretheight*capo
+		out.StrokeLine(image.Point{x - fretwidth/4, ypos},
+			image.Point{x + fretwidth*(fb.Strings-1) + fretwidth/4, ypos}, 8, grey)
+	}
+
+	// Print fret numbers
+	for i := 0; i < fb.Frets+1; i++ {
+		fS := fmt.Sprintf("%2d", i+fb.StartingFret)
+		fH := out.TextHeight()
+		box := rect.Rect{
+			X: x - fretnumWidth - boardShiftX,
+			Y: y + fretheight*i - fH/2,
+			W: borderX,
+			H: fretheight,
+		}
+		out.DrawText(box, fS, black)
+	}
+
+	circleW = circleW * 95 / 100
+
+	// Print note circles and texts
+	for _, note := range fb.Notes {
+		row := note.Fret - fb.StartingFret - 1
+		box := rect.Rect{
+			X: x + note.String*fretwidth - circleW/2,
+			Y: y + row*fretheight + (fretheight-circleW)/2,
+			W: circleW,
+			H: circleW,
+		}
+		colors := noteColors(fb, note)
+		out.FillCircle(box, colors.back)
+
+		text := note.Label(fb.Labels)
+		fW := out.TextWidth(text)
+		fH := out.TextHeight()
+		fbox := rect.Rect{
+			X: x + note.String*fretwidth - fW/2,
+			Y: y + row*fretheight + (fretheight-fH)/2,
+			W: fW,
+			H: fH,
+		}
+		out.DrawText(fbox, text, colors.fore)
+	}
+}
+
+// nucularCanvas draws the diagrams in the GUI
+type nucularCanvas struct {
+	out   *command.Buffer
+	style *style.Style
+	font  font.Face
+}
+
+func (c *nucularCanvas) FillRect(r rect.Rect, col color.RGBA) {
+	c.out.FillRect(r, 0, col)
+}
+
+func (c *nucularCanvas) StrokeLine(p0, p1 image.Point, thickness int, col color.RGBA) {
+	c.out.StrokeLine(p0, p1, thickness, col)
+}
+
+func (c *nucularCanvas) FillCircle(r rect.Rect, col color.RGBA) {
+	c.out.FillCircle(r, col)
+}
+
+func (c *nucularCanvas) DrawText(r rect.Rect, text string, col color.RGBA) {
+	c.out.DrawText(r, text, c.font, col)
+}
+
+func (c *nucularCanvas) SetFontScaling(scaling float64) {
+	c.style.DefaultFont(scaling)
+	c.font = c.style.Font
+	c.style.DefaultFont(c.style.Scaling) // Get the default font back
+}
+
+func (c *nucularCanvas) TextWidth(text string) int {
+	return nucular.FontWidth(c.font, text)
+}
+
+func (c *nucularCanvas) TextHeight() int {
+	return nucular.FontHeight(c.font)
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/aarzilli/nucular/rect"
+	"github.com/kopoli/fretnoter/fretboard"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	exportWidth  = 400
+	exportHeight = 700
+
+	// Font size of the diagram text when scaling is 1.0
+	svgFontSize = 12.0
+)
+
+// svgCanvas writes the diagram as SVG elements
+type svgCanvas struct {
+	buf      bytes.Buffer
+	fontSize float64
+}
+
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func (c *svgCanvas) FillRect(r rect.Rect, col color.RGBA) {
+	fmt.Fprintf(&c.buf, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n",
+		r.X, r.Y, r.W, r.H, svgColor(col))
+}
+
+func (c *svgCanvas) StrokeLine(p0, p1 image.Point, thickness int, col color.RGBA) {
+	fmt.Fprintf(&c.buf, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"%s\" stroke-width=\"%d\"/>\n",
+		p0.X, p0.Y, p1.X, p1.Y, svgColor(col), thickness)
+}
+
+func (c *svgCanvas) FillCircle(r rect.Rect, col color.RGBA) {
+	fmt.Fprintf(&c.buf, "<ellipse cx=\"%.1f\" cy=\"%.1f\" rx=\"%.1f\" ry=\"%.1f\" fill=\"%s\"/>\n",
+		float64(r.X)+float64(r.W)/2, float64(r.Y)+float64(r.H)/2,
+		float64(r.W)/2, float64(r.H)/2, svgColor(col))
+}
+
+func (c *svgCanvas) DrawText(r rect.Rect, text string, col color.RGBA) {
+	fmt.Fprintf(&c.buf, "<text x=\"%d\" y=\"%.1f\" font-family=\"monospace\" font-size=\"%.1f\" fill=\"%s\" xml:space=\"preserve\">%s</text>\n",
+		r.X, float64(r.Y)+c.fontSize*0.8, c.fontSize, svgColor(col), html.EscapeString(text))
+}
+
+func (c *svgCanvas) SetFontScaling(scaling float64) {
+	c.fontSize = svgFontSize * scaling
+}
+
+func (c *svgCanvas) TextWidth(text string) int {
+	// Monospace fonts are roughly 0.6 em wide
+	return int(float64(len([]rune(text))) * c.fontSize * 0.6)
+}
+
+func (c *svgCanvas) TextHeight() int {
+	return int(c.fontSize)
+}
+
+// pngCanvas draws the diagram to an image. The text is drawn with a fixed
+// size font.
+type pngCanvas struct {
+	img  *image.RGBA
+	face font.Face
+}
+
+func (c *pngCanvas) FillRect(r rect.Rect, col color.RGBA) {
+	draw.Draw(c.img, image.Rect(r.X, r.Y, r.X+r.W, r.Y+r.H), image.NewUniform(col),
+		image.Point{}, draw.Over)
+}
+
+func (c *pngCanvas) StrokeLine(p0, p1 image.Point, thickness int, col color.RGBA) {
+	dx := p1.X - p0.X
+	dy := p1.Y - p0.Y
+	steps := dx
+	if steps < 0 {
+		steps = -steps
+	}
+	if dy > steps || -dy > steps {
+		steps = dy
+		if steps < 0 {
+			steps = -steps
+		}
+	}
+	if steps == 0 {
+		steps = 1
+	}
+
+	// Stamp a square brush along the line
+	for i := 0; i <= steps; i++ {
+		x := p0.X + dx*i/steps - thickness/2
+		y := p0.Y + dy*i/steps - thickness/2
+		c.FillRect(rect.Rect{X: x, Y: y, W: thickness, H: thickness}, col)
+	}
+}
+
+func (c *pngCanvas) FillCircle(r rect.Rect, col color.RGBA) {
+	cx := float64(r.X) + float64(r.W)/2
+	cy := float64(r.Y) + float64(r.H)/2
+	rx := float64(r.W) / 2
+	ry := float64(r.H) / 2
+	for y := r.Y; y < r.Y+r.H; y++ {
+		for x := r.X; x < r.X+r.W; x++ {
+			px := (float64(x) + 0.5 - cx) / rx
+			py := (float64(y) + 0.5 - cy) / ry
+			if px*px+py*py <= 1.0 {
+				c.img.SetRGBA(x, y, col)
+			}
+		}
+	}
+}
+
+func (c *pngCanvas) DrawText(r rect.Rect, text string, col color.RGBA) {
+	d := font.Drawer{
+		Dst:  c.img,
+		Src:  image.NewUniform(col),
+		Face: c.face,
+		Dot:  fixed.P(r.X, r.Y+c.face.Metrics().Ascent.Ceil()),
+	}
+	d.DrawString(text)
+}
+
+func (c *pngCanvas) SetFontScaling(scaling float64) {
+}
+
+func (c *pngCanvas) TextWidth(text string) int {
+	return font.MeasureString(c.face, text).Ceil()
+}
+
+func (c *pngCanvas) TextHeight() int {
+	return c.face.Metrics().Height.Ceil()
+}
+
+// Get the size of the exported image. Horizontal boards are wider than
+// they are tall.
+func exportSize(fb *fretboard.FretBoard) (int, int) {
+	if fb.Horizontal {
+		return exportHeight, exportWidth
+	}
+	return exportWidth, exportHeight
+}
+
+// Draw the board title and the diagram below it
+func drawExport(out diagramCanvas, width, height int, title string, fb *fretboard.FretBoard) {
+	out.FillRect(rect.Rect{W: width, H: height}, white)
+
+	out.SetFontScaling(1.2)
+	lines := strings.Split(title, "\n")
+	lineHeight := out.TextHeight() * 5 / 4
+	y := lineHeight / 2
+	for _, line := range lines {
+		out.DrawText(rect.Rect{X: width * 5 / 100, Y: y, W: width, H: lineHeight}, line, black)
+		y += lineHeight
+	}
+
+	drawDiagram(out, rect.Rect{Y: y, W: width, H: height - y}, fb)
+}
+
+// WriteSVG writes the board with its title as an SVG image
+func WriteSVG(w io.Writer, title string, fb *fretboard.FretBoard) error {
+	c := &svgCanvas{}
+	width, height := exportSize(fb)
+	drawExport(c, width, height, title, fb)
+
+	_, err := fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n",
+		width, height, width, height)
+	if err != nil {
+		return err
+	}
+	_, err = c.buf.WriteTo(w)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, "</svg>")
+	return err
+}
+
+// WritePNG writes the board with its title as a PNG image
+func WritePNG(w io.Writer, title string, fb *fretboard.FretBoard) error {
+	width, height := exportSize(fb)
+	c := &pngCanvas{
+		img:  image.NewRGBA(image.Rect(0, 0, width, height)),
+		face: basicfont.Face7x13,
+	}
+	drawExport(c, width, height, title, fb)
+	return png.Encode(w, c.img)
+}
+
+// Export the board to the given file. The format is selected by the file
+// extension.
+func exportBoard(path, title string, fb *fretboard.FretBoard) error {
+	var write func(io.Writer, string, *fretboard.FretBoard) error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".svg":
+		write = WriteSVG
+	case ".png":
+		write = WritePNG
+	default:
+		return fmt.Errorf("unsupported export format '%s', use .svg or .png", filepath.Ext(path))
+	}
+
+	fp, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = write(fp, title, fb)
+	if err != nil {
+		fp.Close()
+		return err
+	}
+	return fp.Close()
+}
+
+var fileNameRe = regexp.MustCompile(`[^A-Za-z0-9#]+`)
+
+// Get a file name for the board from the first line of its title
+func exportFileName(title string) string {
+	name := strings.SplitN(title, "\n", 2)[0]
+	name = strings.Trim(fileNameRe.ReplaceAllString(name, "-"), "-")
+	return strings.Replace(name, "#", "sharp", -1)
+}
@@ -0,0 +1,283 @@
+// Package fretboard places notes on the strings and frets of a stringed
+// instrument.
+package fretboard
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/kopoli/fretnoter/theory"
+)
+
+// NoteType tells how a note is drawn on the board
+type NoteType int
+
+const (
+	NoteUnvoiced NoteType = iota
+	NoteRoot
+	NoteBlack
+	NoteGrey
+	NoteHighlight
+	NoteBass
+)
+
+// NoteTypes has the names of the note types
+var NoteTypes = []string{"Unvoiced", "Root", "Black", "Grey", "Highlight", "Bass"}
+
+func (t NoteType) String() string {
+	if t < 0 || int(t) >= len(NoteTypes) {
+		return NoteTypes[NoteBlack]
+	}
+	return NoteTypes[t]
+}
+
+// ParseNoteType returns the note type of the name, e.g. "Root". The case
+// of the name is ignored.
+func ParseNoteType(name string) (NoteType, error) {
+	for i := range NoteTypes {
+		if strings.EqualFold(NoteTypes[i], name) {
+			return NoteType(i), nil
+		}
+	}
+	return NoteBlack, &UnknownNoteTypeError{Name: name}
+}
+
+// LabelMode tells how the notes are labelled on the board
+type LabelMode int
+
+const (
+	LabelNames LabelMode = iota
+	LabelIntervals
+	LabelDegrees
+	LabelShapes
+)
+
+// LabelModes has the names of the label modes
+var LabelModes = []string{"Names", "Intervals", "Degrees", "Shapes"}
+
+func (m LabelMode) String() string {
+	if m < 0 || int(m) >= len(LabelModes) {
+		return LabelModes[LabelNames]
+	}
+	return LabelModes[m]
+}
+
+// Note is a note at the given string and fret. Pitch is the sounding
+// pitch of the note and Interval its interval from the root, e.g. "b3".
+// Shape is the name of the note relative to the capo.
+type Note struct {
+	String   int
+	Fret     int
+	Name     string
+	Interval string
+	Shape    string
+	Pitch    theory.Pitch
+	Type     NoteType
+}
+
+// Label returns the text of the note in the given label mode. The name is
+// used if the interval or the shape is not known.
+func (n Note) Label(mode LabelMode) string {
+	switch {
+	case mode == LabelIntervals && n.Interval != "":
+		return n.Interval
+	case mode == LabelDegrees && n.Interval != "":
+		return strconv.Itoa(theory.IntervalDegree(n.Interval))
+	case mode == LabelShapes && n.Shape != "":
+		return n.Shape
+	}
+	return n.Name
+}
+
+// FretBoard is a set of notes on the strings of an instrument. The strings
+// are tuned according to Tuning starting from the lowest string. The tuning
+// notes may have octaves, e.g. "E2". The board
+// shows the frets from StartingFret to StartingFret+Frets. The notes are
+// labelled according to Labels.
+//
+// The board is drawn with a vertical neck and the lowest string on the
+// left unless it is Horizontal, in which case the lowest string is at the
+// bottom and the frets run from left to right. LeftHanded boards are
+// mirrored.
+//
+// The strings of the tuning may start at a later fret, see ParseString.
+// With a Capo the strings start at the capo fret.
+type FretBoard struct {
+	Name         string
+	Tuning       []string
+	Strings      int
+	Frets        int
+	StartingFret int
+	Capo         int
+	Labels       LabelMode
+	Horizontal   bool
+	LeftHanded   bool
+	Notes        []Note
+}
+
+// SetNotes marks every position where one of the given notes is played
+// with the given note type. The notes keep the spelling they are given
+// with.
+func (f *FretBoard) SetNotes(notes []string, ntype NoteType) error {
+	notemap := map[int]string{}
+	for i := range notes {
+		pos, err := theory.NotePosition(notes[i])
+		if err != nil {
+			return err
+		}
+		notemap[pos], _ = theory.NormalizeNote(notes[i])
+	}
+
+	open, err := f.StringPitches()
+	if err != nil {
+		return err
+	}
+
+	starts := f.StringStarts()
+	for s := 0; s < f.Strings; s++ {
+		for fr := f.StartingFret; fr <= f.StartingFret+f.Frets; fr++ {
+			// The string doesn't exist below its starting fret
+			if fr < starts[s] {
+				continue
+			}
+			pitch := open[s] + theory.Pitch(fr)
+			if name, ok := notemap[pitch.Position()]; ok {
+				f.Notes = append(f.Notes, Note{
+					String: s,
+					Fret:   fr,
+					Name:   name,
+					Pitch:  pitch,
+					Type:   ntype,
+				})
+			}
+		}
+	}
+
+	f.setShapes()
+	return nil
+}
+
+// AddNote marks the note played at the string and fret with the given note
+// type. An existing note at the same place is replaced.
+func (f *FretBoard) AddNote(str, fret int, ntype NoteType) error {
+	if str < 0 || str >= f.Strings {
+		return &FretError{String: str, Fret: fret, Reason: "the string doesn't exist"}
+	}
+	if fret < f.StringStarts()[str] {
+		return &FretError{String: str, Fret: fret, Reason: "the fret is below the start of the string"}
+	}
+
+	open, err := f.StringPitches()
+	if err != nil {
+		return err
+	}
+	pitch := open[str] + theory.Pitch(fret)
+
+	f.RemoveNote(str, fret)
+	f.Notes = append(f.Notes, Note{
+		String: str,
+		Fret:   fret,
+		Name:   theory.Notes[pitch.Position()],
+		Pitch:  pitch,
+		Type:   ntype,
+	})
+	f.setShapes()
+	return nil
+}
+
+// RemoveNote removes the note at the string and fret if there is one
+func (f *FretBoard) RemoveNote(str, fret int) {
+	for i := range f.Notes {
+		if f.Notes[i].String == str && f.Notes[i].Fret == fret {
+			f.Notes = append(f.Notes[:i], f.Notes[i+1:]...)
+			return
+		}
+	}
+}
+
+// Order of the note types when toggled
+var toggleOrder = []NoteType{NoteBlack, NoteRoot, NoteBass, NoteGrey, NoteHighlight, NoteUnvoiced}
+
+// ToggleNote adds a black note to the string and fret or changes the type
+// of the note there to the next of root, bass, grey, highlighted and
+// unvoiced. An unvoiced note is removed.
+func (f *FretBoard) ToggleNote(str, fret int) error {
+	for _, n := range f.Notes {
+		if n.String != str || n.Fret != fret {
+			continue
+		}
+		for i := range toggleOrder {
+			if toggleOrder[i] == n.Type && i+1 < len(toggleOrder) {
+				return f.AddNote(str, fret, toggleOrder[i+1])
+			}
+		}
+		f.RemoveNote(str, fret)
+		return nil
+	}
+	return f.AddNote(str, fret, toggleOrder[0])
+}
+
+// SetBass marks the notes that are the bass note of a slash chord with
+// NoteBass. The bass note is added to the board if it is not a note of the
+// chord.
+func (f *FretBoard) SetBass(note string) error {
+	pos, err := theory.NotePosition(note)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for i := range f.Notes {
+		if f.Notes[i].Name == "x" || f.Notes[i].Pitch.Position() != pos {
+			continue
+		}
+		f.Notes[i].Type = NoteBass
+		found = true
+	}
+	if !found {
+		return f.SetNotes([]string{note}, NoteBass)
+	}
+	return nil
+}
+
+// Set the names of the notes relative to the capo
+func (f *FretBoard) setShapes() {
+	for i := range f.Notes {
+		n := &f.Notes[i]
+		if f.Capo <= 0 || n.Name == "x" {
+			n.Shape = ""
+			continue
+		}
+		n.Shape, _ = theory.TransposeNote(n.Name, -f.Capo)
+	}
+}
+
+// SetIntervals sets the intervals of the notes on the board. The intervals
+// are given in the same order as the notes.
+func (f *FretBoard) SetIntervals(notes, intervals []string) error {
+	intervalmap := map[int]string{}
+	for i := range notes {
+		pos, err := theory.NotePosition(notes[i])
+		if err != nil {
+			return err
+		}
+		intervalmap[pos] = intervals[i]
+	}
+
+	for i := range f.Notes {
+		if f.Notes[i].Name == "x" {
+			continue
+		}
+		pos, err := theory.NotePosition(f.Notes[i].Name)
+		if err != nil {
+			return err
+		}
+		f.Notes[i].Interval = intervalmap[pos]
+	}
+	return nil
+}
+
+// Clear removes all notes from the board
+func (f *FretBoard) Clear() {
+	f.Notes = nil
+}
@@ -0,0 +1,62 @@
+package fretboard
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoNotes is returned when a scale or a chord without notes is given
+var ErrNoNotes = errors.New("no notes given")
+
+// InvalidStringError is returned when a string of a tuning can't be parsed
+type InvalidStringError struct {
+	String string
+	Reason string
+}
+
+func (e *InvalidStringError) Error() string {
+	return fmt.Sprintf("invalid string '%s': %s", e.String, e.Reason)
+}
+
+// UnknownNoteTypeError is returned when a note type is not in NoteTypes
+type UnknownNoteTypeError struct {
+	Name string
+}
+
+func (e *UnknownNoteTypeError) Error() string {
+	return fmt.Sprintf("unknown note type '%s'", e.Name)
+}
+
+// FretError is returned when a note is placed outside of the board. The
+// strings are numbered from 0.
+type FretError struct {
+	String int
+	Fret   int
+	Reason string
+}
+
+func (e *FretError) Error() string {
+	return fmt.Sprintf("fret %d of string %d: %s", e.Fret, e.String+1, e.Reason)
+}
+
+// InvalidVoicingError is returned when a voicing can't be parsed or doesn't
+// fit the tuning
+type InvalidVoicingError struct {
+	Voicing string
+	Reason  string
+}
+
+func (e *InvalidVoicingError) Error() string {
+	return fmt.Sprintf("invalid voicing '%s': %s", e.Voicing, e.Reason)
+}
+
+// UnsupportedPositionsError is returned when the positions of the system
+// can't be found on the tuning
+type UnsupportedPositionsError struct {
+	System PositionSystem
+	Reason string
+}
+
+func (e *UnsupportedPositionsError) Error() string {
+	return fmt.Sprintf("%s positions are not supported: %s", e.System, e.Reason)
+}
@@ -0,0 +1,44 @@
+package fretboard
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	var se *InvalidStringError
+	if _, _, err := ParseString("G4@x"); !errors.As(err, &se) {
+		t.Errorf("ParseString: got %v, want an InvalidStringError", err)
+	}
+
+	var te *UnknownNoteTypeError
+	if _, err := ParseNoteType("Purple"); !errors.As(err, &te) {
+		t.Errorf("ParseNoteType: got %v, want an UnknownNoteTypeError", err)
+	}
+
+	var ve *InvalidVoicingError
+	if _, err := ParseVoicing("x3a010"); !errors.As(err, &ve) {
+		t.Errorf("ParseVoicing: got %v, want an InvalidVoicingError", err)
+	}
+	v, _ := ParseVoicing("x3201")
+	if _, err := v.Notes(standardTuning); !errors.As(err, &ve) {
+		t.Errorf("Notes: got %v, want an InvalidVoicingError", err)
+	}
+
+	var fe *FretError
+	v, _ = ParseVoicing("2-x-x-x-x-x")
+	if _, err := v.Notes([]string{"G4@5", "D3", "G3", "B3", "D4", "E4"}); !errors.As(err, &fe) || fe.Fret != 2 {
+		t.Errorf("Notes: got %v, want a FretError of fret 2", err)
+	}
+	fb := FretBoard{Strings: 6, Frets: 12, Tuning: standardTuning}
+	if err := fb.AddNote(6, 0, NoteBlack); !errors.As(err, &fe) {
+		t.Errorf("AddNote: got %v, want a FretError", err)
+	}
+
+	if _, err := FindVoicings(standardTuning, nil, DefaultVoicingOptions); !errors.Is(err, ErrNoNotes) {
+		t.Errorf("FindVoicings: got %v, want ErrNoNotes", err)
+	}
+	if _, err := ScalePositions(PositionsCAGED, standardTuning, nil); !errors.Is(err, ErrNoNotes) {
+		t.Errorf("ScalePositions: got %v, want ErrNoNotes", err)
+	}
+}
@@ -0,0 +1,90 @@
+package fretboard
+
+import "strings"
+
+// Tuning is a named tuning of the strings from the lowest
+type Tuning struct {
+	Name  string
+	Notes string
+}
+
+// Instrument has the common tunings of an instrument. The first tuning is
+// the standard tuning.
+type Instrument struct {
+	Name    string
+	Tunings []Tuning
+}
+
+// Instruments are the preset instruments and their tunings
+var Instruments = []Instrument{
+	{"Guitar", []Tuning{
+		{"Standard", "E2 A2 D3 G3 B3 E4"},
+		{"Drop D", "D2 A2 D3 G3 B3 E4"},
+		{"DADGAD", "D2 A2 D3 G3 A3 D4"},
+		{"Open G", "D2 G2 D3 G3 B3 D4"},
+		{"Open D", "D2 A2 D3 F#3 A3 D4"},
+		{"Open E", "E2 B2 E3 G#3 B3 E4"},
+		{"Half step down", "Eb2 Ab2 Db3 Gb3 Bb3 Eb4"},
+	}},
+	{"7-string guitar", []Tuning{
+		{"Standard", "B1 E2 A2 D3 G3 B3 E4"},
+		{"Drop A", "A1 E2 A2 D3 G3 B3 E4"},
+	}},
+	{"8-string guitar", []Tuning{
+		{"Standard", "F#1 B1 E2 A2 D3 G3 B3 E4"},
+		{"Drop E", "E1 B1 E2 A2 D3 G3 B3 E4"},
+	}},
+	{"Bass", []Tuning{
+		{"Standard", "E1 A1 D2 G2"},
+		{"Drop D", "D1 A1 D2 G2"},
+	}},
+	{"5-string bass", []Tuning{
+		{"Standard", "B0 E1 A1 D2 G2"},
+	}},
+	{"6-string bass", []Tuning{
+		{"Standard", "B0 E1 A1 D2 G2 C3"},
+	}},
+	{"Ukulele", []Tuning{
+		{"Standard", "G4 C4 E4 A4"},
+		{"Low G", "G3 C4 E4 A4"},
+		{"Baritone", "D3 G3 B3 E4"},
+	}},
+	{"Mandolin", []Tuning{
+		{"Standard", "G3 D4 A4 E5"},
+	}},
+	{"Banjo", []Tuning{
+		{"Open G", "G4@5 D3 G3 B3 D4"},
+		{"Double C", "G4@5 C3 G3 C4 D4"},
+	}},
+	{"Violin", []Tuning{
+		{"Standard", "G3 D4 A4 E5"},
+	}},
+	{"Viola", []Tuning{
+		{"Standard", "C3 G3 D4 A4"},
+	}},
+	{"Cello", []Tuning{
+		{"Standard", "C2 G2 D3 A3"},
+	}},
+	{"Double bass", []Tuning{
+		{"Standard", "E1 A1 D2 G2"},
+	}},
+}
+
+// PresetName returns the name of the tuning of the instrument, e.g.
+// "Guitar: Drop D"
+func PresetName(instrument Instrument, tuning Tuning) string {
+	return instrument.Name + ": " + tuning.Name
+}
+
+// FindPreset returns the preset tuning with the given name. The case of
+// the name is ignored.
+func FindPreset(name string) (Tuning, bool) {
+	for _, ins := range Instruments {
+		for _, t := range ins.Tunings {
+			if strings.EqualFold(PresetName(ins, t), name) {
+				return t, true
+			}
+		}
+	}
+	return Tuning{}, false
+}
@@ -0,0 +1,93 @@
+package fretboard
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/kopoli/fretnoter/theory"
+)
+
+// The lowest possible pitch of the first string without an octave, B1
+const lowestStringPitch = 35
+
+// ParseString splits a string of a tuning to its note and its starting
+// fret. The starting fret is given after an "@", e.g. the short fifth
+// string of a banjo is "G4@5". Strings without it start at fret 0.
+func ParseString(str string) (string, int, error) {
+	i := strings.IndexByte(str, '@')
+	if i < 0 {
+		return str, 0, nil
+	}
+	start, err := strconv.Atoi(str[i+1:])
+	if err != nil || start < 0 {
+		return "", 0, &InvalidStringError{String: str, Reason: "invalid starting fret"}
+	}
+	return str[:i], start, nil
+}
+
+// StringPitches returns the pitches of fret 0 of the strings. The strings
+// may be given with octaves, e.g. "E2". A string without an octave is the
+// next higher note of its name after the previous string. The first string
+// without an octave is between B1 and A#2.
+//
+// The pitch of a string that starts at a later fret is the pitch its fret 0
+// would have, e.g. D4 for the "G4@5" string of a banjo.
+func StringPitches(tuning []string) ([]theory.Pitch, error) {
+	ret := make([]theory.Pitch, len(tuning))
+	prev := theory.Pitch(lowestStringPitch - 1)
+	for i := range tuning {
+		note, start, err := ParseString(tuning[i])
+		if err != nil {
+			return nil, err
+		}
+		pitch, err := theory.ParsePitch(note)
+		if err != nil {
+			pos, err := theory.NotePosition(note)
+			if err != nil {
+				return nil, err
+			}
+			// Pitch 33 is A1, which is position 0 in theory.Notes
+			pitch = theory.Pitch(33 + pos)
+			for pitch <= prev {
+				pitch += theory.Pitch(len(theory.Notes))
+			}
+		}
+		ret[i] = pitch - theory.Pitch(start)
+		prev = pitch
+	}
+	return ret, nil
+}
+
+// StringStarts returns the starting frets of the strings
+func StringStarts(tuning []string) ([]int, error) {
+	ret := make([]int, len(tuning))
+	for i := range tuning {
+		var err error
+		_, ret[i], err = ParseString(tuning[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// StringPitches returns the pitches of fret 0 of the strings of the board
+func (f *FretBoard) StringPitches() ([]theory.Pitch, error) {
+	return StringPitches(f.Tuning)
+}
+
+// StringStarts returns the starting frets of the strings of the board. The
+// strings start at the capo if there is one. The strings that can't be
+// parsed start at fret 0.
+func (f *FretBoard) StringStarts() []int {
+	ret, err := StringStarts(f.Tuning)
+	if err != nil {
+		ret = make([]int, len(f.Tuning))
+	}
+	for i := range ret {
+		if ret[i] < f.Capo {
+			ret[i] = f.Capo
+		}
+	}
+	return ret
+}
@@ -0,0 +1,251 @@
+package fretboard
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kopoli/fretnoter/theory"
+)
+
+// PositionSystem divides the notes of a scale into hand positions
+type PositionSystem int
+
+const (
+	PositionsNone PositionSystem = iota
+	PositionsCAGED
+	PositionsThreeNPS
+)
+
+// PositionSystems has the names of the position systems
+var PositionSystems = []string{"All notes", "CAGED", "3 notes per string"}
+
+func (p PositionSystem) String() string {
+	if p < 0 || int(p) >= len(PositionSystems) {
+		return PositionSystems[PositionsNone]
+	}
+	return PositionSystems[p]
+}
+
+// Position is a playable subset of the notes of a scale. Frets has the
+// played frets of each string.
+type Position struct {
+	Name  string
+	Frets [][]int
+}
+
+// Contains tells if the fret of the string is played in the position
+func (p Position) Contains(str, fret int) bool {
+	if str < 0 || str >= len(p.Frets) {
+		return false
+	}
+	for _, fr := range p.Frets[str] {
+		if fr == fret {
+			return true
+		}
+	}
+	return false
+}
+
+// lowestFret returns the lowest fret of the position
+func (p Position) lowestFret() int {
+	ret := -1
+	for s := range p.Frets {
+		for _, fr := range p.Frets[s] {
+			if ret < 0 || fr < ret {
+				ret = fr
+			}
+		}
+	}
+	return ret
+}
+
+// The CAGED shapes with the string of the root of the chord shape and the
+// frets of the scale pattern relative to the root. The strings are counted
+// from the lowest string of a standard tuned guitar.
+var cagedShapes = []struct {
+	name   string
+	str    int
+	lo, hi int
+}{
+	{"C shape", 1, -3, 1},
+	{"A shape", 1, -1, 3},
+	{"G shape", 0, -3, 1},
+	{"E shape", 0, -1, 3},
+	{"D shape", 2, -1, 3},
+}
+
+// Semitones between the adjacent strings of a standard tuned guitar
+var guitarIntervals = []theory.Pitch{5, 5, 5, 4, 5}
+
+// Get the string that is the lowest string of a standard tuned guitar, e.g.
+// 1 on a 7-string guitar. The tuning may be transposed, e.g. a half step
+// down, but other tunings such as drop D are not guitar tunings.
+func guitarStringOffset(open []theory.Pitch) (int, bool) {
+	for i := 0; i+len(guitarIntervals) < len(open); i++ {
+		ok := true
+		for j, interval := range guitarIntervals {
+			if open[i+j+1]-open[i+j] != interval {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// SupportedPositions returns the position systems that can be used with the
+// tuning. The CAGED positions need the strings of a standard tuned guitar.
+func SupportedPositions(tuning []string) []PositionSystem {
+	ret := []PositionSystem{PositionsNone}
+	open, err := StringPitches(tuning)
+	if err != nil {
+		return ret
+	}
+	if _, ok := guitarStringOffset(open); ok {
+		ret = append(ret, PositionsCAGED)
+	}
+	return append(ret, PositionsThreeNPS)
+}
+
+// ScalePositions returns the positions of the scale in the given system
+// from the lowest position. The first of the scale notes is the root.
+func ScalePositions(system PositionSystem, tuning []string, scale []string) ([]Position, error) {
+	if len(scale) == 0 {
+		return nil, ErrNoNotes
+	}
+
+	open, err := StringPitches(tuning)
+	if err != nil {
+		return nil, err
+	}
+
+	inScale := map[int]bool{}
+	for i := range scale {
+		pos, err := theory.NotePosition(scale[i])
+		if err != nil {
+			return nil, err
+		}
+		inScale[pos] = true
+	}
+
+	var ret []Position
+	switch system {
+	case PositionsCAGED:
+		ret, err = cagedPositions(open, scale[0], inScale)
+	case PositionsThreeNPS:
+		ret, err = perStringPositions(open, scale, inScale)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].lowestFret() < ret[j].lowestFret()
+	})
+	return ret, nil
+}
+
+// Get the lowest fret of the string where the note is played
+func lowestFretOf(open theory.Pitch, note int) int {
+	return (note - open.Position() + len(theory.Notes)) % len(theory.Notes)
+}
+
+func cagedPositions(open []theory.Pitch, root string, inScale map[int]bool) ([]Position, error) {
+	offset, ok := guitarStringOffset(open)
+	if !ok {
+		return nil, &UnsupportedPositionsError{
+			System: PositionsCAGED,
+			Reason: "the tuning must have the strings of a standard tuned guitar",
+		}
+	}
+	rootpos, err := theory.NotePosition(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []Position
+	for _, shape := range cagedShapes {
+		rootFret := lowestFretOf(open[offset+shape.str], rootpos)
+		if rootFret+shape.lo < 0 {
+			rootFret += len(theory.Notes)
+		}
+
+		p := Position{
+			Name:  shape.name,
+			Frets: make([][]int, len(open)),
+		}
+		for s := range open {
+			for fr := rootFret + shape.lo; fr <= rootFret+shape.hi; fr++ {
+				if inScale[(open[s] + theory.Pitch(fr)).Position()] {
+					p.Frets[s] = append(p.Frets[s], fr)
+				}
+			}
+		}
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
+
+// Get the positions where each string has the same amount of consecutive
+// scale notes. Scales with fewer than six notes have two notes per string
+// and others three.
+func perStringPositions(open []theory.Pitch, scale []string, inScale map[int]bool) ([]Position, error) {
+	perString := 3
+	if len(scale) < 6 {
+		perString = 2
+	}
+
+	var ret []Position
+	for i := range scale {
+		pos, _ := theory.NotePosition(scale[i])
+		start := open[0] + theory.Pitch(lowestFretOf(open[0], pos))
+
+		var p Position
+		for {
+			p = Position{
+				Name:  fmt.Sprintf("From %s", scale[i]),
+				Frets: make([][]int, len(open)),
+			}
+			ok := true
+			pitch := start
+			for s := range open {
+				for n := 0; n < perString; n++ {
+					fr := int(pitch - open[s])
+					if fr < 0 {
+						ok = false
+					}
+					p.Frets[s] = append(p.Frets[s], fr)
+
+					// Move to the next note of the scale
+					pitch++
+					for !inScale[pitch.Position()] {
+						pitch++
+					}
+				}
+			}
+			if ok {
+				break
+			}
+			// Start an octave higher if the pattern doesn't fit the strings
+			start += theory.Pitch(len(theory.Notes))
+		}
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
+
+// SetPosition greys the notes on the board that are not played in the
+// position
+func (f *FretBoard) SetPosition(p Position) {
+	for i := range f.Notes {
+		n := &f.Notes[i]
+		if n.Type != NoteUnvoiced && !p.Contains(n.String, n.Fret) {
+			n.Type = NoteGrey
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package fretboard
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kopoli/fretnoter/theory"
+)
+
+func TestCAGEDPositions(t *testing.T) {
+	scale, err := theory.GetScale("C", "Major (Ionian)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	six, err := ScalePositions(PositionsCAGED, standardTuning, scale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range six {
+		names = append(names, p.Name)
+	}
+	want := []string{"C shape", "A shape", "G shape", "E shape", "D shape"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got the positions %v, want %v", names, want)
+	}
+
+	// The shapes are on the same strings of a 7-string guitar
+	seven, err := ScalePositions(PositionsCAGED, strings.Fields("B1 E2 A2 D3 G3 B3 E4"), scale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seven) != len(six) {
+		t.Fatalf("got %d positions on a 7-string guitar, want %d", len(seven), len(six))
+	}
+	for i := range six {
+		if !reflect.DeepEqual(seven[i].Frets[1:], six[i].Frets) {
+			t.Errorf("%s is %v on a 7-string guitar, want %v above the lowest string",
+				six[i].Name, seven[i].Frets, six[i].Frets)
+		}
+	}
+
+	for _, tuning := range []string{"E1 A1 D2 G2", "G4 C4 E4 A4", "D2 A2 D3 G3 B3 E4"} {
+		_, err := ScalePositions(PositionsCAGED, strings.Fields(tuning), scale)
+		var pe *UnsupportedPositionsError
+		if !errors.As(err, &pe) {
+			t.Errorf("%s: got %v, want an UnsupportedPositionsError", tuning, err)
+		}
+	}
+}
+
+func TestSupportedPositions(t *testing.T) {
+	tests := []struct {
+		tuning string
+		want   []PositionSystem
+	}{
+		{"E2 A2 D3 G3 B3 E4", []PositionSystem{PositionsNone, PositionsCAGED, PositionsThreeNPS}},
+		{"Eb2 Ab2 Db3 Gb3 Bb3 Eb4", []PositionSystem{PositionsNone, PositionsCAGED, PositionsThreeNPS}},
+		{"F#1 B1 E2 A2 D3 G3 B3 E4", []PositionSystem{PositionsNone, PositionsCAGED, PositionsThreeNPS}},
+		{"D2 A2 D3 G3 B3 E4", []PositionSystem{PositionsNone, PositionsThreeNPS}},
+		{"E1 A1 D2 G2", []PositionSystem{PositionsNone, PositionsThreeNPS}},
+	}
+	for _, tt := range tests {
+		got := SupportedPositions(strings.Fields(tt.tuning))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SupportedPositions(%s) = %v, want %v", tt.tuning, got, tt.want)
+		}
+	}
+}
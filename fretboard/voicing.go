@@ -0,0 +1,437 @@
+package fretboard
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kopoli/fretnoter/theory"
+)
+
+// Muted is the fret of a string that is not played in a Voicing
+const Muted = -1
+
+// Voicing is a way to play a chord with one fret or Muted per string. The
+// voicings with a lower Score are easier to play.
+type Voicing struct {
+	Frets []int
+	Score int
+}
+
+// String returns the frets in the usual notation, e.g. "x32010". If any of
+// the frets is above 9, the frets are separated with dashes.
+func (v Voicing) String() string {
+	sep := ""
+	for _, fr := range v.Frets {
+		if fr > 9 {
+			sep = "-"
+		}
+	}
+
+	parts := make([]string, len(v.Frets))
+	for i, fr := range v.Frets {
+		if fr == Muted {
+			parts[i] = "x"
+		} else {
+			parts[i] = strconv.Itoa(fr)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+// ParseVoicing parses frets given in the notation returned by
+// Voicing.String, e.g. "x32010" or "8-10-10-9-8-8"
+func ParseVoicing(frets string) (Voicing, error) {
+	var parts []string
+	if strings.ContainsAny(frets, "- ") {
+		parts = strings.FieldsFunc(frets, func(r rune) bool {
+			return r == '-' || r == ' '
+		})
+	} else {
+		parts = strings.Split(frets, "")
+	}
+
+	var ret Voicing
+	for _, p := range parts {
+		if p == "x" || p == "X" {
+			ret.Frets = append(ret.Frets, Muted)
+			continue
+		}
+		fr, err := strconv.Atoi(p)
+		if err != nil || fr < 0 {
+			return Voicing{}, &InvalidVoicingError{
+				Voicing: frets,
+				Reason:  fmt.Sprintf("invalid fret '%s'", p),
+			}
+		}
+		ret.Frets = append(ret.Frets, fr)
+	}
+	if len(ret.Frets) == 0 {
+		return Voicing{}, &InvalidVoicingError{Voicing: frets, Reason: "no frets given"}
+	}
+	return ret, nil
+}
+
+// Notes returns the sounding notes of the voicing from the lowest pitch
+func (v Voicing) Notes(tuning []string) ([]string, error) {
+	if len(v.Frets) != len(tuning) {
+		return nil, &InvalidVoicingError{
+			Voicing: v.String(),
+			Reason:  fmt.Sprintf("it has %d strings, expected %d", len(v.Frets), len(tuning)),
+		}
+	}
+
+	open, err := StringPitches(tuning)
+	if err != nil {
+		return nil, err
+	}
+	starts, err := StringStarts(tuning)
+	if err != nil {
+		return nil, err
+	}
+
+	var pitches []theory.Pitch
+	for s, fr := range v.Frets {
+		if fr == Muted {
+			continue
+		}
+		if fr < starts[s] {
+			return nil, &FretError{String: s, Fret: fr, Reason: "the fret is below the start of the string"}
+		}
+		pitches = append(pitches, open[s]+theory.Pitch(fr))
+	}
+	sort.SliceStable(pitches, func(i, j int) bool {
+		return pitches[i] < pitches[j]
+	})
+
+	ret := make([]string, len(pitches))
+	for i := range pitches {
+		ret[i] = theory.Notes[pitches[i].Position()]
+	}
+	return ret, nil
+}
+
+// VoicingOptions are the constraints for a playable voicing
+type VoicingOptions struct {
+	// Maximum distance between the lowest and the highest fretted note
+	MaxSpan int
+
+	// Maximum number of fretting fingers. A barre counts as one finger.
+	Fingers int
+
+	// Require the root note on the lowest played string
+	RootInBass bool
+
+	// Maximum number of muted strings
+	MaxMuted int
+
+	// The highest fret to search from
+	MaxFret int
+
+	// Fret of the capo. The strings are played open at the capo and the
+	// frets are searched up to MaxFret above the capo.
+	Capo int
+
+	// Bass note of a slash chord or an inversion, e.g. E for C/E. It is
+	// required on the lowest sounding string instead of the root.
+	Bass string
+}
+
+// DefaultVoicingOptions are the constraints for a typical guitar player
+var DefaultVoicingOptions = VoicingOptions{
+	MaxSpan:    3,
+	Fingers:    4,
+	RootInBass: true,
+	MaxMuted:   2,
+	MaxFret:    12,
+}
+
+// FindVoicings returns the playable voicings of the chord, easiest first.
+// The first of the chord notes is the root. The perfect fifth may be left
+// out of chords with more than four notes.
+func FindVoicings(tuning []string, chord []string, opts VoicingOptions) ([]Voicing, error) {
+	if len(chord) == 0 {
+		return nil, ErrNoNotes
+	}
+
+	open, err := StringPitches(tuning)
+	if err != nil {
+		return nil, err
+	}
+	starts, err := StringStarts(tuning)
+	if err != nil {
+		return nil, err
+	}
+	for i := range starts {
+		if starts[i] < opts.Capo {
+			starts[i] = opts.Capo
+		}
+	}
+
+	tones := map[int]bool{}
+	for i := range chord {
+		pos, err := theory.NotePosition(chord[i])
+		if err != nil {
+			return nil, err
+		}
+		tones[pos] = true
+	}
+	root, _ := theory.NotePosition(chord[0])
+
+	required := map[int]bool{}
+	for pos := range tones {
+		required[pos] = true
+	}
+	if len(required) > 4 {
+		delete(required, (root+7)%len(theory.Notes))
+	}
+
+	// The bass of a slash chord may be outside of the chord
+	bass := root
+	if opts.Bass != "" {
+		bass, err = theory.NotePosition(opts.Bass)
+		if err != nil {
+			return nil, err
+		}
+		tones[bass] = true
+		required[bass] = true
+		opts.RootInBass = true
+	}
+
+	pitchAt := func(s, fr int) theory.Pitch {
+		return open[s] + theory.Pitch(fr)
+	}
+	noteAt := func(s, fr int) int {
+		return pitchAt(s, fr).Position()
+	}
+
+	found := map[string]Voicing{}
+	frets := make([]int, len(tuning))
+
+	var search func(s, lo, hi int)
+	search = func(s, lo, hi int) {
+		if s == len(tuning) {
+			v, ok := rateVoicing(frets, starts, pitchAt, bass, required, opts)
+			if ok {
+				found[v.String()] = v
+			}
+			return
+		}
+
+		frets[s] = Muted
+		search(s+1, lo, hi)
+
+		// The starting fret of the string is played open
+		if tones[noteAt(s, starts[s])] {
+			frets[s] = starts[s]
+			search(s+1, lo, hi)
+		}
+		for fr := lo; fr <= hi; fr++ {
+			if fr > starts[s] && tones[noteAt(s, fr)] {
+				frets[s] = fr
+				search(s+1, lo, hi)
+			}
+		}
+	}
+
+	// Search the fretted notes within each window of the maximum span
+	maxFret := opts.MaxFret + opts.Capo
+	for lo := opts.Capo + 1; lo <= maxFret; lo++ {
+		hi := lo + opts.MaxSpan
+		if hi > maxFret {
+			hi = maxFret
+		}
+		search(0, lo, hi)
+	}
+
+	ret := make([]Voicing, 0, len(found))
+	for _, v := range found {
+		ret = append(ret, v)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Score != ret[j].Score {
+			return ret[i].Score < ret[j].Score
+		}
+		return ret[i].String() < ret[j].String()
+	})
+
+	return ret, nil
+}
+
+// rateVoicing checks if the frets are a playable voicing and scores it.
+// The strings played at their starting fret are open. The bass is the
+// lowest sounding note, which is not on the lowest string in reentrant
+// tunings. The wanted bass note is the root or the bass of a slash chord.
+func rateVoicing(frets, starts []int, pitchAt func(s, fr int) theory.Pitch, wantBass int,
+	required map[int]bool, opts VoicingOptions) (Voicing, bool) {
+
+	muted := 0
+	interiorMuted := 0
+	bass := -1
+	var lowest theory.Pitch
+	minFret, maxFret := -1, -1
+	sounding := map[int]bool{}
+	lastPlayed := -1
+
+	for s, fr := range frets {
+		if fr == Muted {
+			muted++
+			continue
+		}
+		if lastPlayed >= 0 && lastPlayed != s-1 {
+			interiorMuted += s - lastPlayed - 1
+		}
+		lastPlayed = s
+
+		pitch := pitchAt(s, fr)
+		note := pitch.Position()
+		if bass < 0 || pitch < lowest {
+			bass = note
+			lowest = pitch
+		}
+		sounding[note] = true
+
+		if fr > starts[s] {
+			if minFret < 0 || fr < minFret {
+				minFret = fr
+			}
+			if fr > maxFret {
+				maxFret = fr
+			}
+		}
+	}
+
+	if bass < 0 || muted > opts.MaxMuted {
+		return Voicing{}, false
+	}
+	if opts.RootInBass && bass != wantBass {
+		return Voicing{}, false
+	}
+	for pos := range required {
+		if !sounding[pos] {
+			return Voicing{}, false
+		}
+	}
+
+	span := 0
+	if minFret > 0 {
+		span = maxFret - minFret
+	}
+	if span > opts.MaxSpan {
+		return Voicing{}, false
+	}
+
+	fingers := fingerCount(frets, starts, minFret)
+	if fingers > opts.Fingers {
+		return Voicing{}, false
+	}
+
+	score := fingers*2 + span*3 + muted*2 + interiorMuted*6
+	if minFret > 0 {
+		score += minFret - opts.Capo
+	}
+	if bass != wantBass {
+		score += 4
+	}
+
+	return Voicing{
+		Frets: append([]int{}, frets...),
+		Score: score,
+	}, true
+}
+
+// fingerCount returns the number of fingers needed to fret the notes. The
+// notes on the lowest fret are barred if no string between them is played
+// open or muted.
+func fingerCount(frets, starts []int, minFret int) int {
+	if minFret <= 0 {
+		return 0
+	}
+
+	fretted := 0
+	first, last := -1, -1
+	for s, fr := range frets {
+		if fr > starts[s] {
+			fretted++
+		}
+		if fr == minFret {
+			if first < 0 {
+				first = s
+			}
+			last = s
+		}
+	}
+	if first < 0 || first == last {
+		return fretted
+	}
+
+	barred := 0
+	for s := first; s <= last; s++ {
+		if frets[s] < minFret || frets[s] == starts[s] {
+			return fretted
+		}
+		if frets[s] == minFret {
+			barred++
+		}
+	}
+	return fretted - barred + 1
+}
+
+// SetVoicing marks the notes of the voicing on the board. The notes that
+// are the same as the first of the chord notes are marked as roots. Open
+// strings are marked with NoteUnvoiced and muted strings with an unvoiced
+// "x" at the starting fret of the string.
+func (f *FretBoard) SetVoicing(v Voicing, chord []string) error {
+	if len(v.Frets) != f.Strings {
+		return &InvalidVoicingError{
+			Voicing: v.String(),
+			Reason:  fmt.Sprintf("it has %d strings, expected %d", len(v.Frets), f.Strings),
+		}
+	}
+
+	names := map[int]string{}
+	for i := range chord {
+		pos, err := theory.NotePosition(chord[i])
+		if err != nil {
+			return err
+		}
+		names[pos] = chord[i]
+	}
+
+	open, err := f.StringPitches()
+	if err != nil {
+		return err
+	}
+	starts := f.StringStarts()
+
+	for s, fr := range v.Frets {
+		if fr == Muted {
+			f.Notes = append(f.Notes, Note{String: s, Fret: starts[s], Name: "x", Type: NoteUnvoiced})
+			continue
+		}
+
+		pos := (open[s] + theory.Pitch(fr)).Position()
+		note := theory.Notes[pos]
+		if name, ok := names[pos]; ok {
+			note = name
+		}
+
+		ntype := NoteBlack
+		switch {
+		case fr == starts[s]:
+			ntype = NoteUnvoiced
+		case len(chord) > 0 && theory.SameNote(note, chord[0]):
+			ntype = NoteRoot
+		}
+		f.Notes = append(f.Notes, Note{
+			String: s,
+			Fret:   fr,
+			Name:   note,
+			Pitch:  open[s] + theory.Pitch(fr),
+			Type:   ntype,
+		})
+	}
+	f.setShapes()
+	return nil
+}
@@ -0,0 +1,204 @@
+package fretboard
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kopoli/fretnoter/theory"
+)
+
+var standardTuning = strings.Fields("E2 A2 D3 G3 B3 E4")
+
+// Check that the voicing sounds the chord within the constraints
+func checkVoicing(t *testing.T, v Voicing, chord []string, opts VoicingOptions) {
+	t.Helper()
+
+	muted := 0
+	minFret, maxFret := -1, -1
+	for _, fr := range v.Frets {
+		switch {
+		case fr == Muted:
+			muted++
+		case fr > opts.Capo:
+			if minFret < 0 || fr < minFret {
+				minFret = fr
+			}
+			if fr > maxFret {
+				maxFret = fr
+			}
+		}
+	}
+	if muted > opts.MaxMuted {
+		t.Errorf("%s has %d muted strings, the maximum is %d", v, muted, opts.MaxMuted)
+	}
+	if minFret > 0 && maxFret-minFret > opts.MaxSpan {
+		t.Errorf("%s spans %d frets, the maximum is %d", v, maxFret-minFret, opts.MaxSpan)
+	}
+
+	notes, err := v.Notes(standardTuning)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range notes {
+		if !theory.IsChordInScale([]string{n}, chord) {
+			t.Errorf("%s has %s, which is not in %v", v, n, chord)
+		}
+	}
+	if !theory.IsChordInScale(chord, notes) {
+		t.Errorf("%s has the notes %v, which miss some of %v", v, notes, chord)
+	}
+	if opts.RootInBass && !theory.SameNote(notes[0], chord[0]) {
+		t.Errorf("%s has %s in the bass, want %s", v, notes[0], chord[0])
+	}
+}
+
+func TestFindVoicings(t *testing.T) {
+	tests := []struct {
+		chord string
+		want  []string
+	}{
+		{"C Major", []string{"x32010", "x35553"}},
+		{"G Major", []string{"320003", "355433"}},
+		{"A Minor", []string{"x02210"}},
+		{"D 7", []string{"xx0212"}},
+		{"E Minor", []string{"022000"}},
+	}
+	for _, tt := range tests {
+		parts := strings.Fields(tt.chord)
+		chord, err := theory.GetChord(parts[0], parts[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts := DefaultVoicingOptions
+		voicings, err := FindVoicings(standardTuning, chord, opts)
+		if err != nil {
+			t.Errorf("FindVoicings(%s): %v", tt.chord, err)
+			continue
+		}
+
+		found := map[string]bool{}
+		for i, v := range voicings {
+			found[v.String()] = true
+			checkVoicing(t, v, chord, opts)
+			if i > 0 && voicings[i-1].Score > v.Score {
+				t.Errorf("%s: %s is after the harder %s", tt.chord, v, voicings[i-1])
+			}
+		}
+		for _, w := range tt.want {
+			if !found[w] {
+				t.Errorf("FindVoicings(%s) has no %s", tt.chord, w)
+			}
+		}
+	}
+}
+
+func TestFindVoicingsOptions(t *testing.T) {
+	chord, err := theory.GetChord("C", "Major")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := DefaultVoicingOptions
+	opts.MaxSpan = 2
+	opts.MaxMuted = 1
+	voicings, err := FindVoicings(standardTuning, chord, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, v := range voicings {
+		checkVoicing(t, v, chord, opts)
+		found = found || v.String() == "x32010"
+	}
+	if !found {
+		t.Error("no x32010 with the span of 2 and one muted string")
+	}
+
+	opts.MaxMuted = 0
+	voicings, err = FindVoicings(standardTuning, chord, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range voicings {
+		checkVoicing(t, v, chord, opts)
+	}
+
+	// Without the root in the bass, e.g. C/G is 332010
+	opts = DefaultVoicingOptions
+	opts.RootInBass = false
+	voicings, err = FindVoicings(standardTuning, chord, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found = false
+	for _, v := range voicings {
+		checkVoicing(t, v, chord, opts)
+		found = found || v.String() == "332010"
+	}
+	if !found {
+		t.Error("no 332010 without the root in the bass")
+	}
+
+	// The bass of a slash chord is the lowest note
+	opts = DefaultVoicingOptions
+	opts.Bass = "E"
+	voicings, err = FindVoicings(standardTuning, chord, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(voicings) == 0 {
+		t.Fatal("no voicings for C/E")
+	}
+	for _, v := range voicings {
+		notes, err := v.Notes(standardTuning)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if notes[0] != "E" {
+			t.Errorf("%s of C/E has %s in the bass", v, notes[0])
+		}
+	}
+
+	// Only two fingers
+	opts = DefaultVoicingOptions
+	opts.Fingers = 2
+	voicings, err = FindVoicings(standardTuning, chord, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range voicings {
+		if v.String() == "x32010" {
+			t.Errorf("x32010 needs three fingers")
+		}
+	}
+}
+
+func TestParseVoicing(t *testing.T) {
+	tests := []struct {
+		frets string
+		want  []int
+	}{
+		{"x32010", []int{Muted, 3, 2, 0, 1, 0}},
+		{"8-10-10-9-8-8", []int{8, 10, 10, 9, 8, 8}},
+		{"X 12 x", []int{Muted, 12, Muted}},
+	}
+	for _, tt := range tests {
+		v, err := ParseVoicing(tt.frets)
+		if err != nil || !reflect.DeepEqual(v.Frets, tt.want) {
+			t.Errorf("ParseVoicing(%s) = %v, %v, want %v", tt.frets, v.Frets, err, tt.want)
+		}
+	}
+	for _, frets := range []string{"", "x3a010", "x-3-y"} {
+		if _, err := ParseVoicing(frets); err == nil {
+			t.Errorf("ParseVoicing(%s): expected an error", frets)
+		}
+	}
+
+	v, _ := ParseVoicing("x32010")
+	notes, err := v.Notes(standardTuning)
+	want := []string{"C", "E", "G", "C", "E"}
+	if err != nil || !reflect.DeepEqual(notes, want) {
+		t.Errorf("x32010 has the notes %v, %v, want %v", notes, err, want)
+	}
+}
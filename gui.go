@@ -1,30 +1,59 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"image"
-	"image/color"
+	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/aarzilli/nucular"
 	"github.com/aarzilli/nucular/label"
 	"github.com/aarzilli/nucular/rect"
 	"github.com/aarzilli/nucular/style"
+	"github.com/kopoli/fretnoter/fretboard"
+	"github.com/kopoli/fretnoter/midi"
+	"github.com/kopoli/fretnoter/synth"
+	"github.com/kopoli/fretnoter/theory"
 	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
 )
 
 type infoBoard struct {
 	Type string
 
-	FretBoard
+	fretboard.FretBoard
 
 	// List board contents
 	Root       string
 	Scale      string
 	ScaleNotes []string
-	Chords     ChordMap
+	Chords     theory.ChordMap
+
+	// Positions of scale boards
+	Positions []fretboard.Position
+
+	// Voicing board contents
+	Voicings []fretboard.Voicing
+
+	// Scale search board contents
+	ScaleMatches []theory.ScaleMatch
+	notesEdit    nucular.TextEditor
+
+	// Progression board contents. The chords are shown with their easiest
+	// voicings, which are also in Voicings.
+	Progression     []theory.ProgressionChord
+	ChordBoards     []fretboard.FretBoard
+	progressionEdit nucular.TextEditor
+
+	// Custom board title
+	titleEdit nucular.TextEditor
 }
 
 type NewFretBoard struct {
@@ -32,6 +61,7 @@ type NewFretBoard struct {
 	Root    string
 	Scale   string
 	IsScale bool
+	Capo    int
 }
 
 type FretUI struct {
@@ -47,6 +77,9 @@ type FretUI struct {
 	tuning  []string
 	error   string
 
+	// Bass note of the selected slash chord
+	bass string
+
 	width  int
 	height int
 
@@ -54,16 +87,24 @@ type FretUI struct {
 	saveState State
 
 	tuningEdit  nucular.TextEditor
+	preset      string
 	scalesearch string
 	sclist      []string
 	searchEdit  nucular.TextEditor
 
+	// The searched chord symbol if the search parses as one, e.g. "D/F#"
+	symbol   theory.ChordSymbol
+	symbolOK bool
+
 	// A fretboard to add in the next round
 	newFretBoard *NewFretBoard
 }
 
 var (
-	tuningRe = regexp.MustCompile(`[ABCDEFGabcdefg]#?`)
+	// Flats are only recognized after uppercase letters so that lowercase
+	// tunings such as "eadgbe" still work. The notes may have octaves,
+	// e.g. "E2 A2 D3 G3 B3 E4", and starting frets, e.g. "G4@5".
+	tuningRe = regexp.MustCompile(`[A-G](?:##|#|x|♯|bb|b|♭)?\d?(?:@\d+)?|[a-g](?:##|#|x|♯|♭)?\d?(?:@\d+)?`)
 )
 
 func parseTuning(tuning string) ([]string, error) {
@@ -73,247 +114,1503 @@ func parseTuning(tuning string) ([]string, error) {
 	}
 
 	for i := range splits {
-		splits[i] = strings.TrimSpace(splits[i])
-		splits[i] = strings.ToUpper(splits[i])
-
-		found := false
-		for j := range Notes {
-			if splits[i] == Notes[j] {
-				found = true
-			}
+		note, start, err := fretboard.ParseString(splits[i])
+		if err != nil {
+			return nil, err
 		}
-		if !found {
+		note, err = theory.NormalizePitch(note)
+		if err != nil {
 			return nil, fmt.Errorf("invalid note '%s' given", splits[i])
 		}
+		if start > 0 {
+			note = fmt.Sprintf("%s@%d", note, start)
+		}
+		splits[i] = note
 	}
 
 	return splits, nil
 }
 
-func addBoard(tuning []string, root, scale string, isScale bool) (*FretBoard, error) {
-	ret := &FretBoard{
+// Get the tuning as a string that parseTuning accepts. The notes are
+// separated with spaces if they have octaves.
+func formatTuning(tuning []string) string {
+	sep := ""
+	for i := range tuning {
+		if strings.ContainsAny(tuning[i], "0123456789") {
+			sep = " "
+		}
+	}
+	return strings.Join(tuning, sep)
+}
+
+const (
+	defaultFrets = 11
+	maxFrets     = 24
+	maxCapo      = 12
+)
+
+// Get the title line of the capo and the shape that is played with it
+func capoTitle(root, name string, capo int) string {
+	if capo <= 0 {
+		return ""
+	}
+	shape, err := theory.TransposeNote(root, -capo)
+	if err != nil {
+		return fmt.Sprintf("\nCapo %d", capo)
+	}
+	return fmt.Sprintf("\nCapo %d: %s %s shape", capo, shape, name)
+}
+
+// Get the root, the chord and the bass note of a chord symbol such as
+// "F#m7b5" or "D/F#". A chord that is not known is named by the suffix of
+// the symbol.
+func symbolChord(symbol string) (string, string, string, error) {
+	cs, err := theory.ParseChordSymbol(symbol)
+	if err != nil {
+		return "", "", "", err
+	}
+	name, err := cs.Define()
+	if err != nil {
+		return "", "", "", err
+	}
+	return cs.Root, name, cs.Bass, nil
+}
+
+// Define a saved chord that was named by the suffix of its symbol, e.g.
+// "m7b5add11", if it is not known
+func defineSymbolChord(name string) {
+	if _, ok := theory.ChordName(name); ok {
+		return
+	}
+	cs, err := theory.ParseChordSymbol("C" + name)
+	if err != nil || cs.Bass != "" {
+		return
+	}
+	_, _ = cs.Define()
+}
+
+func addBoard(tuning []string, root, scale, bass string, isScale bool, startingFret, frets, capo int) (*fretboard.FretBoard, error) {
+	ret := &fretboard.FretBoard{
 		Strings:      len(tuning),
-		Frets:        11,
-		StartingFret: 0,
+		Frets:        frets,
+		StartingFret: startingFret,
+		Capo:         capo,
 		Tuning:       tuning,
 	}
 
-	var notes []string
+	var notes, intervals []string
 	var err error
 	var boardtype string
 
 	if isScale {
-		notes, err = GetScale(root, scale)
+		notes, err = theory.GetScale(root, scale)
+		if err == nil {
+			intervals, err = theory.ScaleIntervals(root, scale)
+		}
 		boardtype = "scale"
+		if bass != "" {
+			return nil, fmt.Errorf("only chords can have a bass note")
+		}
 	} else {
-		notes, err = GetChord(root, scale)
+		notes, err = theory.GetChord(root, scale)
+		if err == nil {
+			intervals, err = theory.ChordIntervals(root, scale)
+		}
 		boardtype = "chord"
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	err = ret.SetNotes(notes[1:], NoteBlack)
+	err = ret.SetNotes(notes[1:], fretboard.NoteBlack)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ret.SetNotes(notes[:1], fretboard.NoteRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	shown := notes
+	if bass != "" {
+		shown, err = theory.GetSlashChord(root, scale, bass)
+		if err != nil {
+			return nil, err
+		}
+		notes, intervals, err = setBoardBass(ret, notes, intervals, shown[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = ret.SetIntervals(notes, intervals)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Name = fmt.Sprintf("%s %s%s %s\nTuning: %s\nNotes: %s%s",
+		notes[0], scale, slashName(bass), boardtype,
+		formatTuning(tuning),
+		strings.Join(shown, " "),
+		capoTitle(notes[0], scale, capo))
+
+	return ret, nil
+}
+
+// Get the name suffix of the bass note of a slash chord, e.g. "/F#"
+func slashName(bass string) string {
+	if bass == "" {
+		return ""
+	}
+	return "/" + bass
+}
+
+// Mark the bass note of a slash chord on the board. Returns the chord notes
+// and their intervals with the bass note added if it is not in the chord.
+func setBoardBass(fb *fretboard.FretBoard, notes, intervals []string, bass string) ([]string, []string, error) {
+	err := fb.SetBass(bass)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range notes {
+		if theory.SameNote(notes[i], bass) {
+			return notes, intervals, nil
+		}
+	}
+	interval, err := theory.Interval(notes[0], bass)
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(append([]string{}, notes...), bass),
+		append(append([]string{}, intervals...), interval), nil
+}
+
+func addChordListBoard(tuning []string, root, scale string, capo int) (*infoBoard, error) {
+	ret := &infoBoard{
+		Type: TypeList,
+		FretBoard: fretboard.FretBoard{
+			Strings: len(tuning),
+			Capo:    capo,
+			Tuning:  tuning,
+		},
+		Root:  root,
+		Scale: scale,
+	}
+	var err error
+
+	ret.ScaleNotes, err = theory.GetScale(root, scale)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Chords, err = theory.GetChordsInScale(root, scale)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Name = fmt.Sprintf("%s %s chords\nTuning: %s\nNotes: %s%s",
+		ret.ScaleNotes[0], scale, formatTuning(tuning), strings.Join(ret.ScaleNotes, " "),
+		capoTitle(ret.ScaleNotes[0], scale, capo))
+
+	return ret, nil
+}
+
+// Lowest amount of frets shown in voicing boards
+const voicingFrets = 4
+
+func addVoicingBoard(tuning []string, root, chord string, opts fretboard.VoicingOptions, index int) (*infoBoard, error) {
+	notes, err := theory.GetChord(root, chord)
+	if err != nil {
+		return nil, err
+	}
+
+	voicings, err := fretboard.FindVoicings(tuning, notes, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(voicings) == 0 {
+		return nil, fmt.Errorf("no playable voicings found for %s %s%s", notes[0], chord, slashName(opts.Bass))
+	}
+	if index < 0 || index >= len(voicings) {
+		index = 0
+	}
+
+	v := voicings[index]
+	frets := voicingFrets
+	for _, fr := range v.Frets {
+		if fr > frets {
+			frets = fr
+		}
+	}
+
+	ret := &infoBoard{
+		Type: TypeVoicing,
+		FretBoard: fretboard.FretBoard{
+			Strings: len(tuning),
+			Frets:   frets,
+			Capo:    opts.Capo,
+			Tuning:  tuning,
+		},
+		Voicings: voicings,
+	}
+
+	intervals, err := theory.ChordIntervals(root, chord)
+	if err != nil {
+		return nil, err
+	}
+
+	shown := notes
+	if opts.Bass != "" {
+		shown, err = theory.GetSlashChord(root, chord, opts.Bass)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The bass note outside of the chord is named as in the slash chord
+	err = ret.SetVoicing(v, append(append([]string{}, notes...), shown[0]))
+	if err != nil {
+		return nil, err
+	}
+	if opts.Bass != "" {
+		notes, intervals, err = setBoardBass(&ret.FretBoard, notes, intervals, shown[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = ret.SetIntervals(notes, intervals)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Name = fmt.Sprintf("%s %s%s voicing %d/%d: %s\nTuning: %s\nNotes: %s%s",
+		notes[0], chord, slashName(opts.Bass), index+1, len(voicings), v,
+		formatTuning(tuning),
+		strings.Join(shown, " "),
+		capoTitle(notes[0], chord, opts.Capo))
+
+	return ret, nil
+}
+
+// Amount of identified chords shown in the board title
+const identifiedChords = 4
+
+func addIdentifyBoard(tuning []string, shape string, capo int) (*infoBoard, error) {
+	v := fretboard.Voicing{Frets: make([]int, len(tuning))}
+	for i := range v.Frets {
+		v.Frets[i] = fretboard.Muted
+	}
+	if shape != "" {
+		var err error
+		v, err = fretboard.ParseVoicing(shape)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The strings can't be played below the capo
+	for i := range v.Frets {
+		if v.Frets[i] != fretboard.Muted && v.Frets[i] < capo {
+			v.Frets[i] = fretboard.Muted
+		}
+	}
+
+	notes, err := v.Notes(tuning)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []theory.ChordMatch
+	var chordNotes, intervals []string
+	if len(notes) > 0 {
+		matches, err = theory.IdentifyChord(notes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(matches) > 0 {
+		chordNotes, err = theory.GetChord(matches[0].Root, matches[0].Chord)
+		if err != nil {
+			return nil, err
+		}
+		intervals, err = theory.ChordIntervals(matches[0].Root, matches[0].Chord)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	frets := defaultFrets
+	for _, fr := range v.Frets {
+		if fr > frets {
+			frets = fr
+		}
+	}
+
+	ret := &infoBoard{
+		Type: TypeIdentify,
+		FretBoard: fretboard.FretBoard{
+			Strings: len(tuning),
+			Frets:   frets,
+			Capo:    capo,
+			Tuning:  tuning,
+		},
+	}
+
+	err = ret.SetVoicing(v, chordNotes)
+	if err != nil {
+		return nil, err
+	}
+	err = ret.SetIntervals(chordNotes, intervals)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, identifiedChords)
+	for i := 0; i < len(matches) && i < identifiedChords; i++ {
+		names = append(names, matches[i].Name())
+	}
+	if len(names) == 0 {
+		names = append(names, "-")
+	}
+
+	ret.Name = fmt.Sprintf("Identify: %s\nTuning: %s\nChords: %s",
+		v, formatTuning(tuning), strings.Join(names, ", "))
+	if capo > 0 {
+		ret.Name += fmt.Sprintf("\nCapo %d", capo)
+	}
+
+	return ret, nil
+}
+
+var (
+	notesRe = regexp.MustCompile(`^[A-Ga-g](?:##|#|x|♯|bb|b|♭)?$`)
+)
+
+// Parse the note names separated by spaces or commas, e.g. "C D# Eb"
+func parseNotes(notes string) ([]string, error) {
+	splits := strings.FieldsFunc(notes, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	if len(splits) == 0 {
+		return nil, fmt.Errorf("no notes given")
+	}
+
+	for i := range splits {
+		if !notesRe.MatchString(splits[i]) {
+			return nil, fmt.Errorf("invalid note '%s' given", splits[i])
+		}
+		note, err := theory.NormalizeNote(splits[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid note '%s' given", splits[i])
+		}
+		splits[i] = note
+	}
+
+	return splits, nil
+}
+
+func addFindScalesBoard(notes string) (*infoBoard, error) {
+	ret := &infoBoard{
+		Type: TypeFind,
+	}
+	ret.notesEdit.Flags = nucular.EditField
+	ret.notesEdit.Maxlen = 64
+	ret.notesEdit.Buffer = []rune(notes)
+
+	var err error
+	ret.ScaleNotes, err = parseNotes(notes)
 	if err != nil {
 		return nil, err
 	}
 
-	err = ret.SetNotes([]string{root}, NoteRoot)
+	ret.ScaleMatches, err = theory.FindScales(ret.ScaleNotes)
 	if err != nil {
 		return nil, err
 	}
 
-	ret.Name = fmt.Sprintf("%s %s %s\nTuning: %s\nNotes: %s",
-		root, scale, boardtype,
-		strings.Join(tuning, ""),
-		strings.Join(notes, " "))
+	ret.Name = fmt.Sprintf("Scales with notes\nNotes: %s\nMatches: %d",
+		strings.Join(ret.ScaleNotes, " "), len(ret.ScaleMatches))
 
 	return ret, nil
 }
 
-func addChordListBoard(tuning []string, root, scale string) (*infoBoard, error) {
+func addProgressionBoard(tuning []string, root, scale, progression string, opts fretboard.VoicingOptions) (*infoBoard, error) {
 	ret := &infoBoard{
-		Type:  TypeList,
+		Type: TypeProgression,
+		FretBoard: fretboard.FretBoard{
+			Strings: len(tuning),
+			Capo:    opts.Capo,
+			Tuning:  tuning,
+		},
 		Root:  root,
 		Scale: scale,
 	}
-	var err error
+	ret.progressionEdit.Flags = nucular.EditField
+	ret.progressionEdit.Maxlen = 64
+	ret.progressionEdit.Buffer = []rune(progression)
+
+	var err error
+	ret.Progression, err = theory.ParseProgression(progression, root, scale)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(ret.Progression))
+	for i, c := range ret.Progression {
+		vb, err := addVoicingBoard(tuning, c.Root, c.Chord, opts, 0)
+		if err != nil {
+			return nil, err
+		}
+		ret.ChordBoards = append(ret.ChordBoards, vb.FretBoard)
+		ret.Voicings = append(ret.Voicings, vb.Voicings[0])
+		names[i] = c.Name()
+	}
+
+	ret.Name = fmt.Sprintf("%s %s progression: %s\nTuning: %s\nChords: %s%s",
+		root, scale, progression, formatTuning(tuning), strings.Join(names, " - "),
+		capoTitle(root, scale, opts.Capo))
+
+	return ret, nil
+}
+
+// Get the default progression of the scale from its I, vi, IV and V degrees
+func defaultProgression(root, scale string) (string, error) {
+	numerals, err := theory.ScaleNumerals(root, scale)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{numerals[0], numerals[5], numerals[3], numerals[4]}, "-"), nil
+}
+
+// Get the notes and the name of the scale or chord of the overlay layer
+func layerNotes(l OverlayLayer) ([]string, string, error) {
+	if l.IsScale {
+		notes, err := theory.GetScale(l.Root, l.Name)
+		if err != nil {
+			return nil, "", err
+		}
+		return notes, fmt.Sprintf("%s %s scale", notes[0], l.Name), nil
+	}
+	notes, err := theory.GetChord(l.Root, l.Name)
+	if err != nil {
+		return nil, "", err
+	}
+	return notes, fmt.Sprintf("%s %s chord", notes[0], l.Name), nil
+}
+
+// Create a board of the overlay drawn over the base. The notes of both are
+// black with the root of the overlay marked, the notes only in the base are
+// grey and the notes only in the overlay are highlighted. The notes are
+// labelled by their intervals from the root of the overlay.
+func addOverlayBoard(tuning []string, base, overlay OverlayLayer, startingFret, frets, capo int) (*fretboard.FretBoard, error) {
+	baseNotes, baseName, err := layerNotes(base)
+	if err != nil {
+		return nil, err
+	}
+	overNotes, overName, err := layerNotes(overlay)
+	if err != nil {
+		return nil, err
+	}
+
+	contains := func(notes []string, note string) bool {
+		for i := range notes {
+			if theory.SameNote(notes[i], note) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var roots, common, outside, baseOnly []string
+	for i, n := range overNotes {
+		switch {
+		case !contains(baseNotes, n):
+			outside = append(outside, n)
+		case i == 0:
+			roots = append(roots, n)
+		default:
+			common = append(common, n)
+		}
+	}
+	for _, n := range baseNotes {
+		if !contains(overNotes, n) {
+			baseOnly = append(baseOnly, n)
+		}
+	}
+
+	ret := &fretboard.FretBoard{
+		Strings:      len(tuning),
+		Frets:        frets,
+		StartingFret: startingFret,
+		Capo:         capo,
+		Tuning:       tuning,
+	}
+	layers := []struct {
+		notes []string
+		ntype fretboard.NoteType
+	}{
+		{baseOnly, fretboard.NoteGrey},
+		{common, fretboard.NoteBlack},
+		{roots, fretboard.NoteRoot},
+		{outside, fretboard.NoteHighlight},
+	}
+	for _, l := range layers {
+		err = ret.SetNotes(l.notes, l.ntype)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	notes := append(append([]string{}, overNotes...), baseOnly...)
+	intervals := make([]string, len(notes))
+	for i := range notes {
+		intervals[i], err = theory.Interval(overNotes[0], notes[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !overlay.IsScale {
+		chordIntervals, err := theory.ChordIntervals(overlay.Root, overlay.Name)
+		if err != nil {
+			return nil, err
+		}
+		copy(intervals, chordIntervals)
+	}
+	err = ret.SetIntervals(notes, intervals)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Name = fmt.Sprintf("%s over %s\nTuning: %s\nNotes: %s\nOutside: %s",
+		overName, baseName, formatTuning(tuning),
+		strings.Join(overNotes, " "), strings.Join(outside, " "))
+	if len(outside) == 0 {
+		ret.Name = strings.TrimSuffix(ret.Name, "\nOutside: ")
+	}
+	if capo > 0 {
+		ret.Name += fmt.Sprintf("\nCapo %d", capo)
+	}
+
+	return ret, nil
+}
+
+// Default title of custom boards
+const customTitle = "Custom"
+
+// Create a board of the given notes. The notes below the capo or the start
+// of their strings are not shown.
+func addCustomBoard(tuning []string, title string, notes []CustomNote, startingFret, frets, capo int) (*infoBoard, error) {
+	if title == "" {
+		title = customTitle
+	}
+	if frets <= 0 {
+		frets = defaultFrets
+	}
+
+	ret := &infoBoard{
+		Type: TypeCustom,
+		FretBoard: fretboard.FretBoard{
+			Strings:      len(tuning),
+			Frets:        frets,
+			StartingFret: startingFret,
+			Capo:         capo,
+			Tuning:       tuning,
+		},
+	}
+	ret.titleEdit.Flags = nucular.EditField
+	ret.titleEdit.Maxlen = 64
+	ret.titleEdit.Buffer = []rune(title)
+
+	starts := ret.StringStarts()
+	for _, n := range notes {
+		ntype, err := fretboard.ParseNoteType(n.Type)
+		if err != nil {
+			return nil, err
+		}
+		if n.String >= 0 && n.String < len(starts) && n.Fret < starts[n.String] {
+			continue
+		}
+		err = ret.AddNote(n.String, n.Fret, ntype)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The note names from the lowest
+	sorted := append([]fretboard.Note{}, ret.Notes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Pitch < sorted[j].Pitch
+	})
+	var names []string
+	seen := map[string]bool{}
+	for _, n := range sorted {
+		if !seen[n.Name] {
+			seen[n.Name] = true
+			names = append(names, n.Name)
+		}
+	}
+	if len(names) == 0 {
+		names = append(names, "-")
+	}
+
+	ret.Name = fmt.Sprintf("%s\nTuning: %s\nNotes: %s", title, formatTuning(tuning),
+		strings.Join(names, " "))
+	if capo > 0 {
+		ret.Name += fmt.Sprintf("\nCapo %d", capo)
+	}
+
+	return ret, nil
+}
+
+func voicingOptions(bs BoardState) fretboard.VoicingOptions {
+	ret := fretboard.DefaultVoicingOptions
+	if bs.MaxSpan > 0 {
+		ret.MaxSpan = bs.MaxSpan
+	}
+	if bs.Fingers > 0 {
+		ret.Fingers = bs.Fingers
+	}
+	if bs.MaxMuted != nil {
+		ret.MaxMuted = *bs.MaxMuted
+	}
+	ret.RootInBass = bs.RootInBass
+	ret.Capo = bs.Capo
+	ret.Bass = bs.Bass
+	return ret
+}
+
+// Create the board described by the saved board state
+func boardFromState(bs BoardState) (*infoBoard, error) {
+	tuning, err := parseTuning(bs.Tuning)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret *infoBoard
+	switch bs.Type {
+	case TypeScale, TypeChord:
+		var fb *fretboard.FretBoard
+		fb, err = addBoard(tuning, bs.Root, bs.Name, bs.Bass, bs.Type == TypeScale, bs.StartingFret, bs.Frets, bs.Capo)
+		if err == nil {
+			ret = &infoBoard{
+				Type:      bs.Type,
+				FretBoard: *fb,
+			}
+		}
+		if err == nil && bs.Type == TypeScale {
+			ret.Positions, err = setScalePosition(&ret.FretBoard, bs.Root, bs.Name,
+				positionSystem(bs.Positions), bs.Position)
+
+			// The positions are not shown if the tuning doesn't have them
+			var pe *fretboard.UnsupportedPositionsError
+			if errors.As(err, &pe) {
+				err = nil
+			}
+		}
+	case TypeList:
+		ret, err = addChordListBoard(tuning, bs.Root, bs.Name, bs.Capo)
+	case TypeVoicing:
+		ret, err = addVoicingBoard(tuning, bs.Root, bs.Name, voicingOptions(bs), bs.Voicing)
+	case TypeIdentify:
+		ret, err = addIdentifyBoard(tuning, bs.Shape, bs.Capo)
+	case TypeFind:
+		ret, err = addFindScalesBoard(bs.Notes)
+	case TypeProgression:
+		ret, err = addProgressionBoard(tuning, bs.Root, bs.Name, bs.Progression, voicingOptions(bs))
+	case TypeCustom:
+		ret, err = addCustomBoard(tuning, bs.Name, bs.CustomNotes, bs.StartingFret, bs.Frets, bs.Capo)
+	case TypeOverlay:
+		if bs.Base == nil || bs.Overlay == nil {
+			return nil, fmt.Errorf("overlay board needs two scales or chords")
+		}
+		var fb *fretboard.FretBoard
+		fb, err = addOverlayBoard(tuning, *bs.Base, *bs.Overlay, bs.StartingFret, bs.Frets, bs.Capo)
+		if err == nil {
+			ret = &infoBoard{
+				Type:      bs.Type,
+				FretBoard: *fb,
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown board type '%s'", bs.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Labels = labelMode(bs.Labels)
+	ret.Horizontal = bs.Horizontal
+	ret.LeftHanded = bs.LeftHanded
+	for i := range ret.ChordBoards {
+		ret.ChordBoards[i].Labels = ret.Labels
+		ret.ChordBoards[i].Horizontal = ret.Horizontal
+		ret.ChordBoards[i].LeftHanded = ret.LeftHanded
+	}
+	return ret, nil
+}
+
+// Transpose the saved board by the given amount of semitones. The notes of
+// scale search boards and the layers of overlay boards are transposed and
+// the boards without a root or notes are not changed.
+func transposeState(bs *BoardState, steps int) error {
+	if bs.Root != "" {
+		root, err := theory.TransposeNote(bs.Root, steps)
+		if err != nil {
+			return err
+		}
+		bs.Root = root
+		bs.Voicing = 0
+	}
+	if bs.Bass != "" {
+		bass, err := theory.TransposeNote(bs.Bass, steps)
+		if err != nil {
+			return err
+		}
+		bs.Bass = bass
+	}
+	for _, l := range []**OverlayLayer{&bs.Base, &bs.Overlay} {
+		if *l == nil {
+			continue
+		}
+		layer := **l
+		root, err := theory.TransposeNote(layer.Root, steps)
+		if err != nil {
+			return err
+		}
+		layer.Root = root
+		*l = &layer
+	}
+	if bs.Type == TypeFind && bs.Notes != "" {
+		notes, err := parseNotes(bs.Notes)
+		if err != nil {
+			return err
+		}
+		for i := range notes {
+			notes[i], err = theory.TransposeNote(notes[i], steps)
+			if err != nil {
+				return err
+			}
+		}
+		bs.Notes = strings.Join(notes, " ")
+	}
+	return nil
+}
+
+// Transpose the root and all boards of the saved state by the given amount
+// of semitones. If the key is given, the roots are spelled relative to it,
+// e.g. the IV of the new key Bb is Eb. The state is not changed if any of
+// the boards can't be transposed.
+func transposeWorkspace(s *State, steps int, key string) error {
+	// Spell the note relative to the new key
+	respell := func(note *string) error {
+		if key == "" || s.Root == "" || *note == "" {
+			return nil
+		}
+		dist, err := theory.NoteDistance(s.Root, *note)
+		if err != nil {
+			return err
+		}
+		*note, err = theory.TransposeNote(key, dist)
+		return err
+	}
+
+	boards := append([]BoardState{}, s.Boards...)
+	for i := range boards {
+		root, bass := boards[i].Root, boards[i].Bass
+		err := transposeState(&boards[i], steps)
+		if err != nil {
+			return err
+		}
+		if key != "" {
+			boards[i].Root, boards[i].Bass = root, bass
+			err = respell(&boards[i].Root)
+			if err == nil {
+				err = respell(&boards[i].Bass)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if s.Bass != "" {
+		bass, err := theory.TransposeNote(s.Bass, steps)
+		if err != nil {
+			return err
+		}
+		if key != "" {
+			bass = s.Bass
+			err = respell(&bass)
+			if err != nil {
+				return err
+			}
+		}
+		s.Bass = bass
+	}
+	if key != "" {
+		s.Root = key
+	} else if s.Root != "" {
+		root, err := theory.TransposeNote(s.Root, steps)
+		if err != nil {
+			return err
+		}
+		s.Root = root
+	}
+	s.Boards = boards
+	return nil
+}
+
+// Get the semitones to transpose from the root. The target is either the
+// amount of semitones, e.g. "-2" or "+3", or the key to transpose to, which
+// is also returned.
+func transposeSteps(root, target string) (int, string, error) {
+	if steps, err := strconv.Atoi(target); err == nil {
+		return steps, "", nil
+	}
+	key, err := theory.NormalizeNote(target)
+	if err != nil {
+		return 0, "", err
+	}
+	if root == "" {
+		return 0, "", fmt.Errorf("can't transpose to '%s' without a root", target)
+	}
+	steps, err := theory.NoteDistance(root, key)
+	if err != nil {
+		return 0, "", err
+	}
+	return steps, key, nil
+}
+
+// Grey the notes of the scale board that are not in the position of the
+// system. Returns the positions of the system.
+func setScalePosition(fb *fretboard.FretBoard, root, scale string, system fretboard.PositionSystem, index int) ([]fretboard.Position, error) {
+	if system == fretboard.PositionsNone {
+		return nil, nil
+	}
+
+	notes, err := theory.GetScale(root, scale)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := fretboard.ScalePositions(system, fb.Tuning, notes)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(positions) {
+		index = 0
+	}
+
+	fb.SetPosition(positions[index])
+	fb.Name += fmt.Sprintf("\n%s: %s (%d/%d)", system, positions[index].Name,
+		index+1, len(positions))
+	return positions, nil
+}
+
+// Get the position system by its name or "3nps"
+func positionSystem(name string) fretboard.PositionSystem {
+	if strings.EqualFold(name, "3nps") {
+		return fretboard.PositionsThreeNPS
+	}
+	for i := range fretboard.PositionSystems {
+		if strings.EqualFold(fretboard.PositionSystems[i], name) {
+			return fretboard.PositionSystem(i)
+		}
+	}
+	return fretboard.PositionsNone
+}
+
+// Get the label mode by its name. Unknown names label the notes by their
+// names.
+func labelMode(name string) fretboard.LabelMode {
+	for i := range fretboard.LabelModes {
+		if strings.EqualFold(fretboard.LabelModes[i], name) {
+			return fretboard.LabelMode(i)
+		}
+	}
+	return fretboard.LabelNames
+}
+
+func (f *FretUI) setDirty() {
+	f.dirty = time.Now()
+}
+
+func (f *FretUI) saveConfig() {
+	if !f.dirty.IsZero() && time.Now().After(f.dirty.Add(time.Millisecond*500)) {
+		fmt.Println("Saving!")
+		err := Save(&f.saveState)
+		if err != nil {
+			f.error = fmt.Sprintf("Could not save configuration: %v", err)
+		}
+		f.dirty = time.Time{}
+	}
+}
+
+func (f *FretUI) drawFretDiagram(w *nucular.Window, fb *fretboard.FretBoard) (rect.Rect, bool) {
+	bounds, out := w.Custom(style.WidgetStateInactive)
+	if out == nil {
+		return bounds, false
+	}
+
+	drawDiagram(&nucularCanvas{out: out, style: w.Master().Style()}, bounds, fb)
+	return bounds, true
+}
+
+// Get the string and the fret that was clicked on the diagram
+func clickedCell(w *nucular.Window, bounds rect.Rect, fb *infoBoard) (int, int, bool) {
+	in := w.Input()
+	if in == nil || !in.Mouse.IsClickInRect(mouse.ButtonLeft, bounds) {
+		return 0, 0, false
+	}
+	return newDiagramLayout(bounds, &fb.FretBoard).cellAt(in.Mouse.Pos)
+}
+
+// Set the fret of the string on an identification board. Clicking the
+// same fret again mutes the string.
+func (f *FretUI) toggleShapeFret(idx, str, fret int) {
+	if fret < f.boards[idx].StringStarts()[str] {
+		return
+	}
+
+	bs := &f.saveState.Boards[idx]
+	v, err := fretboard.ParseVoicing(bs.Shape)
+	if err != nil || len(v.Frets) != f.boards[idx].Strings {
+		v.Frets = make([]int, f.boards[idx].Strings)
+		for i := range v.Frets {
+			v.Frets[i] = fretboard.Muted
+		}
+	}
+
+	if v.Frets[str] == fret {
+		v.Frets[str] = fretboard.Muted
+	} else {
+		v.Frets[str] = fret
+	}
+	bs.Shape = v.String()
+	f.updateBoard(idx)
+}
+
+// Add a note to the string and fret of a custom board or change the type of
+// the note there. See fretboard.ToggleNote.
+func (f *FretUI) toggleCustomNote(idx, str, fret int) {
+	starts := f.boards[idx].StringStarts()
+	if fret < starts[str] {
+		return
+	}
+
+	fb := f.boards[idx].FretBoard
+	fb.Notes = append([]fretboard.Note{}, fb.Notes...)
+	err := fb.ToggleNote(str, fret)
+	if err != nil {
+		f.error = fmt.Sprintf("Error: %v", err)
+		return
+	}
+
+	// Keep the notes that are hidden below the capo
+	bs := &f.saveState.Boards[idx]
+	var notes []CustomNote
+	for _, n := range bs.CustomNotes {
+		if n.String >= 0 && n.String < len(starts) && n.Fret < starts[n.String] {
+			notes = append(notes, n)
+		}
+	}
+	for _, n := range fb.Notes {
+		notes = append(notes, CustomNote{String: n.String, Fret: n.Fret, Type: n.Type.String()})
+	}
+	bs.CustomNotes = notes
+	f.updateBoard(idx)
+}
+
+// Add a custom board with the notes of the board
+func (f *FretUI) customizeBoard(idx int) {
+	bs := f.saveState.Boards[idx]
+	ib := &f.boards[idx]
+
+	var notes []CustomNote
+	for _, n := range ib.Notes {
+		if n.Name == "x" {
+			continue
+		}
+		notes = append(notes, CustomNote{String: n.String, Fret: n.Fret, Type: n.Type.String()})
+	}
+	if bs.Type == TypeCustom {
+		notes = append([]CustomNote{}, bs.CustomNotes...)
+	}
+
+	frets := ib.Frets
+	if frets < defaultFrets {
+		frets = defaultFrets
+	}
+	err := f.addBoardState(BoardState{
+		Name:         strings.SplitN(ib.Name, "\n", 2)[0],
+		Type:         TypeCustom,
+		Tuning:       bs.Tuning,
+		Labels:       bs.Labels,
+		Horizontal:   bs.Horizontal,
+		LeftHanded:   bs.LeftHanded,
+		Capo:         bs.Capo,
+		StartingFret: ib.StartingFret,
+		Frets:        frets,
+		CustomNotes:  notes,
+	})
+	if err != nil {
+		f.error = fmt.Sprintf("Error: %v", err)
+		return
+	}
+	f.error = ""
+}
+
+// Export the board as SVG and PNG images to the export directory. Scales
+// and chords are also exported as MIDI files.
+func (f *FretUI) exportBoard(idx int) {
+	dir, err := getExportDir()
+	if err != nil {
+		f.error = fmt.Sprintf("Error: %v", err)
+		return
+	}
+
+	bs := f.saveState.Boards[idx]
+	fb := &f.boards[idx].FretBoard
+	path := filepath.Join(dir, exportFileName(fb.Name))
+	for _, ext := range []string{".svg", ".png"} {
+		err = exportBoard(path+ext, fb.Name, fb)
+		if err != nil {
+			f.error = fmt.Sprintf("Error: %v", err)
+			return
+		}
+	}
+	if bs.Type != TypeScale && bs.Type != TypeChord {
+		f.error = fmt.Sprintf("Exported to: %s.{svg,png}", path)
+		return
+	}
+
+	mf, err := scaleChordMIDI(fb.Tuning, bs.Root, bs.Name, bs.Bass, bs.Type == TypeScale,
+		defaultTempo, defaultNoteLength)
+	if err == nil {
+		err = writeMIDIFile(path+".mid", mf)
+	}
+	if err != nil {
+		f.error = fmt.Sprintf("Error: %v", err)
+		return
+	}
+	f.error = fmt.Sprintf("Exported to: %s.{svg,png,mid}", path)
+}
+
+// Export the chords of the chord list or progression board as a MIDI file
+func (f *FretUI) exportChordList(idx int) {
+	dir, err := getExportDir()
+	if err != nil {
+		f.error = fmt.Sprintf("Error: %v", err)
+		return
+	}
+
+	ib := &f.boards[idx]
+	path := filepath.Join(dir, exportFileName(ib.Name)+".mid")
+	var mf *midi.File
+	if ib.Type == TypeProgression {
+		mf, err = progressionMIDI(ib, defaultTempo, defaultNoteLength)
+	} else {
+		mf, err = chordListMIDI(ib, defaultTempo, defaultNoteLength)
+	}
+	if err == nil {
+		err = writeMIDIFile(path, mf)
+	}
+	if err != nil {
+		f.error = fmt.Sprintf("Error: %v", err)
+		return
+	}
+	f.error = fmt.Sprintf("Exported to: %s", path)
+}
+
+// Play the board with an external audio player
+func (f *FretUI) playBoard(idx int) {
+	bs := f.saveState.Boards[idx]
+	ib := &f.boards[idx]
+
+	var events []synth.Event
+	var err error
+	switch bs.Type {
+	case TypeScale, TypeChord:
+		events, err = scaleChordEvents(ib.Tuning, bs.Root, bs.Name, bs.Bass, bs.Type == TypeScale, defaultStyle(bs.Type))
+	case TypeProgression:
+		events, err = progressionEvents(ib.ChordBoards)
+	default:
+		var pitches []int
+		pitches, err = boardPitches(&ib.FretBoard)
+		if err == nil {
+			events, err = styleEvents(pitches, defaultStyle(bs.Type))
+		}
+	}
+	if err != nil {
+		f.error = fmt.Sprintf("Error: %v", err)
+		return
+	}
+
+	_, err = playEvents(events, os.Stderr)
+	if err != nil {
+		f.error = fmt.Sprintf("Error: %v", err)
+		return
+	}
+	f.error = ""
+}
+
+func (f *FretUI) FretWidget(w *nucular.Window, title string, idx int) int {
+	var deleteidx = -1
+	if sw := w.GroupBegin(title, nucular.WindowBorder|nucular.WindowNoScrollbar); sw != nil {
+		sw.Row(55).Ratio(0.60, 0.10, 0.10, 0.10, 0.10)
+		sw.Label(title, "LT")
+		if sw.Button(label.T("Custom"), false) {
+			f.customizeBoard(idx)
+		}
+		if sw.Button(label.T("Play"), false) {
+			f.playBoard(idx)
+		}
+		if sw.Button(label.T("Export"), false) {
+			f.exportBoard(idx)
+		}
+		if sw.Button(label.T("Close"), false) {
+			deleteidx = idx
+		} else {
+			f.labelControls(sw, idx)
+			f.orientationControls(sw, idx)
+			f.capoControls(sw, idx)
+			if f.saveState.Boards[idx].Root != "" {
+				f.transposeControls(sw, idx)
+			}
+			switch f.boards[idx].Type {
+			case TypeVoicing:
+				f.bassControls(sw, idx)
+				f.voicingControls(sw, idx)
+			case TypeIdentify:
+				sw.Row(25).Dynamic(1)
+				sw.Label("Click the frets to identify the chord", "LC")
+			case TypeScale:
+				f.fretRangeControls(sw, idx)
+				f.positionControls(sw, idx)
+				f.overlayControls(sw, idx)
+			case TypeChord:
+				f.bassControls(sw, idx)
+				f.fretRangeControls(sw, idx)
+				f.overlayControls(sw, idx)
+			case TypeCustom:
+				f.titleControls(sw, idx)
+				f.fretRangeControls(sw, idx)
+				sw.Row(25).Dynamic(1)
+				sw.Label("Click the frets to add notes and change their types", "LC")
+			default:
+				f.fretRangeControls(sw, idx)
+			}
+
+			sw.Row(0).Dynamic(1)
+			bounds, ok := f.drawFretDiagram(sw, &f.boards[idx].FretBoard)
+			if ok && f.boards[idx].Type == TypeIdentify {
+				if str, fret, ok := clickedCell(sw, bounds, &f.boards[idx]); ok {
+					f.toggleShapeFret(idx, str, fret)
+				}
+			}
+			if ok && f.boards[idx].Type == TypeCustom {
+				if str, fret, ok := clickedCell(sw, bounds, &f.boards[idx]); ok {
+					f.toggleCustomNote(idx, str, fret)
+				}
+			}
+		}
+		sw.GroupEnd()
+	}
+	return deleteidx
+}
+
+func (f *FretUI) labelControls(w *nucular.Window, idx int) {
+	bs := &f.saveState.Boards[idx]
+	w.Row(25).Dynamic(len(fretboard.LabelModes))
+	for i, name := range fretboard.LabelModes {
+		mode := fretboard.LabelMode(i)
+		if w.OptionText(name, f.boards[idx].Labels == mode) && f.boards[idx].Labels != mode {
+			bs.Labels = ""
+			if mode != fretboard.LabelNames {
+				bs.Labels = name
+			}
+			f.updateBoard(idx)
+		}
+	}
+}
+
+func (f *FretUI) orientationControls(w *nucular.Window, idx int) {
+	bs := &f.saveState.Boards[idx]
+	w.Row(25).Dynamic(2)
+	changed := w.CheckboxText("Horizontal", &bs.Horizontal)
+	changed = w.CheckboxText("Left-handed", &bs.LeftHanded) || changed
+	if changed {
+		f.updateBoard(idx)
+	}
+}
+
+// Offer to draw the scale or chord selected in the top bar over the scale
+// or chord of the board
+func (f *FretUI) overlayControls(w *nucular.Window, idx int) {
+	bs := f.saveState.Boards[idx]
+	w.Row(25).Dynamic(1)
+	if !w.Button(label.T(fmt.Sprintf("Overlay %s %s", f.root, f.scale)), false) {
+		return
+	}
+	err := f.addBoardState(BoardState{
+		Type:         TypeOverlay,
+		Tuning:       bs.Tuning,
+		Labels:       bs.Labels,
+		Horizontal:   bs.Horizontal,
+		LeftHanded:   bs.LeftHanded,
+		Capo:         bs.Capo,
+		StartingFret: bs.StartingFret,
+		Frets:        bs.Frets,
+		Base:         &OverlayLayer{Root: bs.Root, Name: bs.Name, IsScale: bs.Type == TypeScale},
+		Overlay:      &OverlayLayer{Root: f.root, Name: f.scale, IsScale: f.isScale},
+	})
+	if err != nil {
+		f.error = fmt.Sprintf("Error: %v", err)
+		return
+	}
+	f.error = ""
+}
+
+func (f *FretUI) titleControls(w *nucular.Window, idx int) {
+	ib := &f.boards[idx]
+	w.Row(25).Ratio(0.2, 0.8)
+	w.Label("Title:", "LC")
+	a := ib.titleEdit.Edit(w)
+	if a&nucular.EditCommitted != 0 {
+		f.saveState.Boards[idx].Name = string(ib.titleEdit.Buffer)
+		f.updateBoard(idx)
+	}
+}
+
+func (f *FretUI) capoControls(w *nucular.Window, idx int) {
+	bs := &f.saveState.Boards[idx]
+	w.Row(25).Dynamic(1)
+	if w.PropertyInt("Capo:", 0, &bs.Capo, maxCapo, 1, 1) {
+		f.updateBoard(idx)
+	}
+}
+
+func (f *FretUI) transposeControls(w *nucular.Window, idx int) {
+	bs := &f.saveState.Boards[idx]
+	w.Row(25).Ratio(0.4, 0.15, 0.15, 0.3)
+	w.Label("Transpose:", "LC")
+	if w.Button(label.T("-"), false) {
+		f.transposeBoard(idx, -1)
+	}
+	if w.Button(label.T("+"), false) {
+		f.transposeBoard(idx, 1)
+	}
+	if w := w.Combo(label.T(bs.Root), 400, nil); w != nil {
+		w.Row(30).Dynamic(1)
+		for i := range theory.RootNotes {
+			if w.MenuItem(label.TA(theory.RootNotes[i], "LC")) {
+				f.setBoardRoot(idx, theory.RootNotes[i])
+			}
+		}
+	}
+}
+
+// Select the inversion of the chord or keep the bass note of a slash chord
+// that is not in the chord
+func (f *FretUI) bassControls(w *nucular.Window, idx int) {
+	bs := &f.saveState.Boards[idx]
+	notes, err := theory.GetChord(bs.Root, bs.Name)
+	if err != nil {
+		return
+	}
+
+	items := make([]string, len(notes))
+	items[0] = "Root position"
+	for i := 1; i < len(notes); i++ {
+		items[i] = fmt.Sprintf("%s (inversion %d)", notes[i], i)
+	}
+	sel := 0
+	if bs.Bass != "" {
+		sel = -1
+		for i := range notes {
+			if theory.SameNote(notes[i], bs.Bass) {
+				sel = i
+			}
+		}
+		if sel < 0 {
+			items = append(items, bs.Bass)
+			sel = len(items) - 1
+		}
+	}
+
+	w.Row(25).Ratio(0.3, 0.7)
+	w.Label("Bass:", "LC")
+	if s := w.ComboSimple(items, sel, 25); s != sel {
+		bs.Bass = ""
+		if s > 0 && s < len(notes) {
+			bs.Bass = notes[s]
+		}
+		bs.Voicing = 0
+		f.updateBoard(idx)
+	}
+}
+
+// Change the root of the board and move the bass of a slash chord by the
+// same interval
+func (f *FretUI) setBoardRoot(idx int, root string) {
+	bs := &f.saveState.Boards[idx]
+	if bs.Bass != "" {
+		steps, err := theory.NoteDistance(bs.Root, root)
+		if err == nil {
+			bs.Bass, err = theory.TransposeNote(bs.Bass, steps)
+		}
+		if err != nil {
+			f.error = fmt.Sprintf("Error: %v", err)
+			return
+		}
+	}
+	bs.Root = root
+	bs.Voicing = 0
+	f.updateBoard(idx)
+}
 
-	ret.ScaleNotes, err = GetScale(root, scale)
+// Transpose the board by the given amount of semitones
+func (f *FretUI) transposeBoard(idx, steps int) {
+	err := transposeState(&f.saveState.Boards[idx], steps)
 	if err != nil {
-		return nil, err
+		f.error = fmt.Sprintf("Error: %v", err)
+		return
 	}
+	f.updateBoard(idx)
+}
 
-	ret.Chords, err = GetChordsInScale(root, scale)
+// Transpose all boards by the given amount of semitones. See
+// transposeWorkspace for the key.
+func (f *FretUI) transposeAll(steps int, key string) {
+	err := transposeWorkspace(&f.saveState, steps, key)
 	if err != nil {
-		return nil, err
+		f.error = fmt.Sprintf("Error: %v", err)
+		return
+	}
+	f.root = f.saveState.Root
+	f.bass = f.saveState.Bass
+	for i := range f.boards {
+		f.updateBoard(i)
 	}
-
-	ret.Name = fmt.Sprintf("%s %s chords\nTuning: %s\nNotes: %s",
-		root, scale, strings.Join(tuning, ""), strings.Join(ret.ScaleNotes, " "))
-
-	return ret, nil
 }
 
-func (f *FretUI) setDirty() {
-	f.dirty = time.Now()
+func (f *FretUI) fretRangeControls(w *nucular.Window, idx int) {
+	bs := &f.saveState.Boards[idx]
+	w.Row(25).Dynamic(2)
+	w.PropertyInt("Start fret:", 0, &bs.StartingFret, maxFrets, 1, 1)
+	w.PropertyInt("Frets:", 1, &bs.Frets, maxFrets, 1, 1)
+	if bs.StartingFret != f.boards[idx].StartingFret || bs.Frets != f.boards[idx].Frets {
+		f.updateBoard(idx)
+	}
 }
 
-func (f *FretUI) saveConfig() {
-	if !f.dirty.IsZero() && time.Now().After(f.dirty.Add(time.Millisecond*500)) {
-		fmt.Println("Saving!")
-		err := Save(&f.saveState)
-		if err != nil {
-			f.error = fmt.Sprintf("Could not save configuration: %v", err)
+func (f *FretUI) positionControls(w *nucular.Window, idx int) {
+	bs := &f.saveState.Boards[idx]
+	count := len(f.boards[idx].Positions)
+
+	// Only the position systems of the tuning are offered
+	systems := fretboard.SupportedPositions(f.boards[idx].Tuning)
+	names := make([]string, len(systems))
+	cur := 0
+	for i := range systems {
+		names[i] = systems[i].String()
+		if systems[i] == positionSystem(bs.Positions) {
+			cur = i
 		}
-		f.dirty = time.Time{}
 	}
-}
 
-func (f *FretUI) drawFretDiagram(w *nucular.Window, fb *infoBoard) {
-	bounds, out := w.Custom(style.WidgetStateInactive)
-	if out == nil {
+	w.Row(25).Ratio(0.55, 0.15, 0.15, 0.15)
+	if sel := w.ComboSimple(names, cur, 25); sel != cur {
+		bs.Positions = ""
+		if systems[sel] != fretboard.PositionsNone {
+			bs.Positions = systems[sel].String()
+		}
+		bs.Position = 0
+		f.updateBoard(idx)
 		return
 	}
-
-	mw := w.Master()
-	s := mw.Style()
-
-	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
-	black := color.RGBA{0, 0, 0, 0xff}
-	red := color.RGBA{0xff, 0, 0, 0xff}
-	grey := color.RGBA{0x80, 0x80, 0x80, 0xff}
-
-	type noteColor struct {
-		back color.RGBA
-		fore color.RGBA
+	if w.Button(label.T("<"), false) && bs.Position > 0 {
+		bs.Position--
+		f.updateBoard(idx)
 	}
-
-	circleColors := map[NoteType]noteColor{
-		NoteUnvoiced: {white, black},
-		NoteRoot:     {red, black},
-		NoteBlack:    {black, white},
-		NoteGrey:     {grey, white},
+	if w.Button(label.T(">"), false) && bs.Position < count-1 {
+		bs.Position++
+		f.updateBoard(idx)
 	}
-
-	borderX := bounds.W * 10 / 100
-	borderY := bounds.H * 5 / 100
-
-	boardBounds := rect.Rect{
-		X: bounds.X + borderX,
-		Y: bounds.Y + borderY,
-		W: bounds.W - (borderX * 2),
-		H: bounds.H - (borderY * 2),
+	if count > 0 {
+		w.Label(fmt.Sprintf("%d/%d", bs.Position+1, count), "CC")
+	} else {
+		w.Label("-", "CC")
 	}
+}
 
-	fretwidth := boardBounds.W / fb.Strings
-	fretheight := boardBounds.H / (fb.Frets + 1)
-
-	// Get a font that is relatively scaled (the 12.0 is from Style.DefaultFont)
-	fontscaling := (float64(fretheight) * 0.4) / 12.0
-	s.DefaultFont(fontscaling)
-	fnt := s.Font
-	s.DefaultFont(s.Scaling) // Get the default font back
-
-	// Maximum width of the fret number box
-	fretnumWidth := nucular.FontWidth(fnt, "00") + (bounds.W * 5 / 100)
-
-	// Shift the board to the right a bit so it isn't on top of the numbers
-	boardShiftX := bounds.W * 2 / 100
-
-	x := boardBounds.X + fretwidth/2 + boardShiftX
-	y := boardBounds.Y + fretheight
-
-	// Draw the background
-	out.FillRect(bounds, 0, white)
-
-	// there is some rounding error between this and boardBounds.Max()
-	maxy := y + fretheight*(fb.Frets)
-	maxx := x + fretwidth*(fb.Strings-1)
+func (f *FretUI) voicingControls(w *nucular.Window, idx int) {
+	bs := &f.saveState.Boards[idx]
+	changed := false
 
-	// Print fret grid
-	for i := 0; i < fb.Strings; i++ {
-		xpos := x + fretwidth*i
-		start := image.Point{xpos, y}
-		stop := image.Point{xpos, maxy}
-		out.StrokeLine(start, stop, 2, black)
+	w.Row(25).Ratio(0.15, 0.15, 0.35, 0.35)
+	if w.Button(label.T("<"), false) && bs.Voicing > 0 {
+		bs.Voicing--
+		changed = true
 	}
-	for i := 0; i < fb.Frets+1; i++ {
-		ypos := y + fretheight*i
-		start := image.Point{x, ypos}
-		stop := image.Point{maxx, ypos}
-		out.StrokeLine(start, stop, 2, black)
+	if w.Button(label.T(">"), false) && bs.Voicing < len(f.boards[idx].Voicings)-1 {
+		bs.Voicing++
+		changed = true
 	}
-
-	// Print fret numbers
-	for i := 0; i < fb.Frets+1; i++ {
-		fS := fmt.Sprintf("%2d", i+fb.StartingFret)
-		fH := nucular.FontHeight(fnt)
-		box := rect.Rect{
-			X: x - fretnumWidth - boardShiftX,
-			Y: y + fretheight*i - fH/2,
-			W: borderX,
-			H: fretheight,
-		}
-		out.DrawText(box, fS, fnt, black)
+	if w.CheckboxText("Root in bass", &bs.RootInBass) {
+		bs.Voicing = 0
+		changed = true
 	}
-
-	circleW := fretheight
-	if fretheight > fretwidth {
-		circleW = fretwidth
+	opts := voicingOptions(*bs)
+	if w.PropertyInt("Span:", 1, &opts.MaxSpan, 6, 1, 1) {
+		bs.MaxSpan = opts.MaxSpan
+		bs.Voicing = 0
+		changed = true
 	}
-	circleW = circleW * 95 / 100
-
-	// Print note circles and texts
-	for _, note := range fb.Notes {
-		box := rect.Rect{
-			X: x + note.String*fretwidth - circleW/2,
-			Y: y + (note.Fret-1)*fretheight + (fretheight-circleW)/2,
-			W: circleW,
-			H: circleW,
-		}
-		out.FillCircle(box, circleColors[note.Type].back)
 
-		fW := nucular.FontWidth(fnt, note.Name)
-		fH := nucular.FontHeight(fnt)
-		fbox := rect.Rect{
-			X: x + note.String*fretwidth - fW/2,
-			Y: y + (note.Fret-1)*fretheight + (fretheight-fH)/2,
-			W: fW,
-			H: fH,
-		}
-		out.DrawText(fbox, note.Name, fnt, circleColors[note.Type].fore)
+	w.Row(25).Dynamic(2)
+	if w.PropertyInt("Fingers:", 1, &opts.Fingers, 4, 1, 1) {
+		bs.Fingers = opts.Fingers
+		bs.Voicing = 0
+		changed = true
+	}
+	if w.PropertyInt("Muted:", 0, &opts.MaxMuted, len(f.boards[idx].Tuning), 1, 1) {
+		muted := opts.MaxMuted
+		bs.MaxMuted = &muted
+		bs.Voicing = 0
+		changed = true
 	}
-}
 
-func (f *FretUI) FretWidget(w *nucular.Window, title string, idx int) int {
-	var deleteidx = -1
-	if sw := w.GroupBegin(title, nucular.WindowBorder|nucular.WindowNoScrollbar); sw != nil {
-		sw.Row(55).Ratio(0.90, 0.10)
-		sw.Label(title, "LT")
-		if sw.Button(label.T("Close"), false) {
-			deleteidx = idx
-		} else {
-			sw.Row(0).Dynamic(1)
-			f.drawFretDiagram(sw, &f.boards[idx])
-		}
-		sw.GroupEnd()
+	if changed {
+		f.updateBoard(idx)
 	}
-	return deleteidx
 }
 
 func (f *FretUI) ChordListWidget(w *nucular.Window, title string, idx int) int {
 	var deleteidx = -1
 	if sw := w.GroupBegin(title, nucular.WindowBorder|nucular.WindowNoScrollbar); sw != nil {
-		sw.Row(55).Ratio(0.90, 0.10)
+		sw.Row(55).Ratio(0.60, 0.20, 0.10, 0.10)
 		sw.Label(title, "LT")
+		if sw.Button(label.T("Progression"), false) {
+			f.addProgression(idx)
+		}
+		if sw.Button(label.T("Export"), false) {
+			f.exportChordList(idx)
+		}
 		if sw.Button(label.T("Close"), false) {
 			deleteidx = idx
 		} else {
+			f.capoControls(sw, idx)
+			f.transposeControls(sw, idx)
+			capo := f.boards[idx].Capo
 			for _, note := range f.boards[idx].ScaleNotes {
 				ch := f.boards[idx].Chords[note]
 				sw.Row(20).Dynamic(1)
-				sw.Label(note, "LT")
+				heading := note
+				if shape, err := theory.TransposeNote(note, -capo); err == nil && capo > 0 {
+					heading = fmt.Sprintf("%s (%s shape)", note, shape)
+				}
+				sw.Label(heading, "LT")
 				chordsperrow := 3
 				for j := range ch {
 					if (j % chordsperrow) == 0 {
@@ -325,6 +1622,7 @@ func (f *FretUI) ChordListWidget(w *nucular.Window, title string, idx int) int {
 							Root:    note,
 							Scale:   ch[j],
 							IsScale: false,
+							Capo:    capo,
 						}
 					}
 				}
@@ -335,30 +1633,263 @@ func (f *FretUI) ChordListWidget(w *nucular.Window, title string, idx int) int {
 	return deleteidx
 }
 
-// Add a new fretboard data to display and save
-func (f *FretUI) AddFretBoard(tuning []string, root, scale string, isScale bool) error {
-	fb, err := addBoard(tuning, root, scale, isScale)
+// Add a progression board of the scale of the chord list board
+func (f *FretUI) addProgression(idx int) {
+	bs := f.saveState.Boards[idx]
+	progression, err := defaultProgression(bs.Root, bs.Name)
+	if err == nil {
+		err = f.addBoardState(BoardState{
+			Name:        bs.Name,
+			Type:        TypeProgression,
+			Root:        bs.Root,
+			Tuning:      bs.Tuning,
+			Capo:        bs.Capo,
+			Progression: progression,
+			RootInBass:  fretboard.DefaultVoicingOptions.RootInBass,
+		})
+	}
 	if err != nil {
-		return err
+		f.error = fmt.Sprintf("Error: %v", err)
+		return
+	}
+	f.error = ""
+}
+
+func (f *FretUI) ProgressionWidget(w *nucular.Window, title string, idx int) int {
+	var deleteidx = -1
+	if sw := w.GroupBegin(title, nucular.WindowBorder|nucular.WindowNoScrollbar); sw != nil {
+		sw.Row(55).Ratio(0.70, 0.10, 0.10, 0.10)
+		sw.Label(title, "LT")
+		if sw.Button(label.T("Play"), false) {
+			f.playBoard(idx)
+		}
+		if sw.Button(label.T("Export"), false) {
+			f.exportChordList(idx)
+		}
+		if sw.Button(label.T("Close"), false) {
+			deleteidx = idx
+		} else {
+			ib := &f.boards[idx]
+			f.labelControls(sw, idx)
+			f.capoControls(sw, idx)
+			f.transposeControls(sw, idx)
+
+			sw.Row(25).Ratio(0.2, 0.8)
+			sw.Label("Chords:", "LC")
+			a := ib.progressionEdit.Edit(sw)
+			if a&nucular.EditCommitted != 0 {
+				progression := string(ib.progressionEdit.Buffer)
+				if _, err := theory.ParseProgression(progression, ib.Root, ib.Scale); err != nil {
+					f.error = fmt.Sprintf("Error: %v", err)
+				} else {
+					f.error = ""
+					f.saveState.Boards[idx].Progression = progression
+					f.updateBoard(idx)
+				}
+			}
+
+			count := len(ib.ChordBoards)
+			sw.Row(20).Dynamic(count)
+			for _, c := range ib.Progression {
+				sw.Label(c.Numeral, "CC")
+			}
+			sw.Row(20).Dynamic(count)
+			for _, c := range ib.Progression {
+				sw.Label(c.Name(), "CC")
+			}
+			sw.Row(0).Dynamic(count)
+			for i := range ib.ChordBoards {
+				f.drawFretDiagram(sw, &ib.ChordBoards[i])
+			}
+		}
+		sw.GroupEnd()
+	}
+	return deleteidx
+}
+
+func (f *FretUI) FindScalesWidget(w *nucular.Window, title string, idx int) int {
+	var deleteidx = -1
+	if sw := w.GroupBegin(title, nucular.WindowBorder); sw != nil {
+		sw.Row(55).Ratio(0.90, 0.10)
+		sw.Label(title, "LT")
+		if sw.Button(label.T("Close"), false) {
+			deleteidx = idx
+		} else {
+			ib := &f.boards[idx]
+			sw.Row(25).Ratio(0.2, 0.8)
+			sw.Label("Notes:", "LC")
+			a := ib.notesEdit.Edit(sw)
+			if a&nucular.EditCommitted != 0 {
+				notes := string(ib.notesEdit.Buffer)
+				if _, err := parseNotes(notes); err != nil {
+					f.error = fmt.Sprintf("Error: %v", err)
+				} else {
+					f.error = ""
+					f.saveState.Boards[idx].Notes = notes
+					f.updateBoard(idx)
+				}
+			}
+
+			exact := true
+			sw.Row(20).Dynamic(1)
+			sw.Label("Exact matches:", "LT")
+			for _, m := range ib.ScaleMatches {
+				if exact && !m.Exact {
+					exact = false
+					sw.Row(20).Dynamic(1)
+					sw.Label("Scales with more notes:", "LT")
+				}
+				sw.Row(20).Dynamic(1)
+				text := m.Name()
+				if !m.Exact {
+					text += " (+" + strings.Join(m.Extra, " ") + ")"
+				}
+				if sw.Button(label.TA(text, "LC"), false) {
+					f.newFretBoard = &NewFretBoard{
+						Tuning:  f.tuning,
+						Root:    m.Root,
+						Scale:   m.Scale,
+						IsScale: true,
+					}
+				}
+			}
+		}
+		sw.GroupEnd()
 	}
+	return deleteidx
+}
 
-	var ib infoBoard
-	ib.FretBoard = *fb
-	f.boards = append(f.boards, ib)
+// Add a new fretboard data to display and save. Only chords may have a
+// bass note.
+func (f *FretUI) AddFretBoard(tuning []string, root, scale, bass string, isScale bool, capo int) error {
 	tp := TypeScale
 	if !isScale {
 		tp = TypeChord
 	}
-	f.saveState.Boards = append(f.saveState.Boards, BoardState{
+	return f.addBoardState(BoardState{
 		Name:   scale,
 		Type:   tp,
 		Root:   root,
-		Tuning: strings.Join(f.tuning, ""),
+		Tuning: formatTuning(tuning),
+		Frets:  defaultFrets,
+		Capo:   capo,
+		Bass:   bass,
 	})
+}
+
+// Add a new board described by the board state
+func (f *FretUI) addBoardState(bs BoardState) error {
+	ib, err := boardFromState(bs)
+	if err != nil {
+		return err
+	}
+
+	f.boards = append(f.boards, *ib)
+	f.saveState.Boards = append(f.saveState.Boards, bs)
 	f.setDirty()
 	return nil
 }
 
+// Recreate the board after its saved state has been changed
+func (f *FretUI) updateBoard(idx int) {
+	ib, err := boardFromState(f.saveState.Boards[idx])
+	if err != nil {
+		f.error = fmt.Sprintf("Error: %v", err)
+		return
+	}
+	f.boards[idx] = *ib
+	f.setDirty()
+}
+
+// Get the name of the preset that has the same pitches as the tuning or an
+// empty string
+func presetOf(tuning []string) string {
+	pitches, err := fretboard.StringPitches(tuning)
+	if err != nil {
+		return ""
+	}
+	for _, ins := range fretboard.Instruments {
+		for _, t := range ins.Tunings {
+			pt, err := parseTuning(t.Notes)
+			if err != nil {
+				continue
+			}
+			ppitches, err := fretboard.StringPitches(pt)
+			if err == nil && fmt.Sprint(ppitches) == fmt.Sprint(pitches) {
+				return fretboard.PresetName(ins, t)
+			}
+		}
+	}
+	return ""
+}
+
+func (f *FretUI) setPreset(name string) {
+	f.preset = name
+	f.saveState.Preset = name
+	f.setDirty()
+}
+
+// Select the tuning from the instrument presets
+func (f *FretUI) presetCombo(w *nucular.Window) {
+	title := f.preset
+	if title == "" {
+		title = "Custom"
+	}
+
+	if w := w.Combo(label.T(title), 600, nil); w != nil {
+		for _, ins := range fretboard.Instruments {
+			w.Row(25).Dynamic(1)
+			w.Label(ins.Name, "LC")
+			for _, t := range ins.Tunings {
+				w.Row(25).Dynamic(1)
+				if !w.MenuItem(label.TA(fmt.Sprintf("    %s (%s)", t.Name, t.Notes), "LC")) {
+					continue
+				}
+				tuning, err := parseTuning(t.Notes)
+				if err != nil {
+					f.error = fmt.Sprintf("Error: %v", err)
+					continue
+				}
+				f.tuning = tuning
+				f.tuningEdit.Buffer = []rune(formatTuning(tuning))
+				f.saveState.Tuning = formatTuning(tuning)
+				f.setPreset(fretboard.PresetName(ins, t))
+			}
+		}
+	}
+}
+
+// Select a "Scale: " or "Chord: " item of the scale and chord list
+func (f *FretUI) selectScaleChord(item string) {
+	ret := strings.Replace(item, "Scale: ", "", 1)
+	f.isScale = (ret != item)
+
+	f.scale = strings.Replace(ret, "Chord: ", "", 1)
+	f.saveState.ScaleChord = f.scale
+	f.setBass("")
+}
+
+// Set the bass note of the selected slash chord
+func (f *FretUI) setBass(bass string) {
+	f.bass = bass
+	f.saveState.Bass = bass
+	f.setDirty()
+}
+
+// Select the root, the chord and the bass note of a chord symbol
+func (f *FretUI) selectSymbol(cs theory.ChordSymbol) {
+	name, err := cs.Define()
+	if err != nil {
+		f.error = fmt.Sprintf("Error: %v", err)
+		return
+	}
+	f.error = ""
+	f.root = cs.Root
+	f.saveState.Root = f.root
+	f.selectScaleChord("Chord: " + name)
+	f.setBass(cs.Bass)
+}
+
 func (f *FretUI) update(w *nucular.Window) {
 	for _, e := range w.Input().Keyboard.Keys {
 		switch {
@@ -367,11 +1898,16 @@ func (f *FretUI) update(w *nucular.Window) {
 		}
 	}
 
-	ratios := []float64{0.1, 0.4, 0.2, 0.1, 0.1, 0.1}
+	ratios := []float64{0.07, 0.17, 0.12, 0.12, 0.07, 0.07, 0.07, 0.07, 0.07, 0.09, 0.08}
 	w.Row(30).Ratio(ratios...)
 	w.Label("Root", "LC")
 	w.Label("Scale or Chord", "LC")
 	w.Label("Tuning", "LC")
+	w.Label("Instrument", "LC")
+	w.Label("", "LC")
+	w.Label("", "LC")
+	w.Label("", "LC")
+	w.Label("", "LC")
 	w.Label("", "LC")
 	w.Label("", "LC")
 	w.Label("Columns", "LC")
@@ -380,48 +1916,54 @@ func (f *FretUI) update(w *nucular.Window) {
 
 	if w := w.Combo(label.T(f.root), 400, nil); w != nil {
 		w.Row(30).Dynamic(1)
-		for i := range Notes {
-			if w.MenuItem(label.TA(Notes[i], "LC")) {
-				f.root = Notes[i]
+		for i := range theory.RootNotes {
+			if w.MenuItem(label.TA(theory.RootNotes[i], "LC")) {
+				f.root = theory.RootNotes[i]
 				f.saveState.Root = f.root
-				f.setDirty()
+				f.setBass("")
 			}
 		}
 	}
 
-	if w := w.Combo(label.T(f.scale), 1200, nil); w != nil {
+	var err error
+	if w := w.Combo(label.T(f.scale+slashName(f.bass)), 1200, nil); w != nil {
 		w.Row(30).Dynamic(1)
 		f.searchEdit.Active = true
 		a := f.searchEdit.Edit(w)
 		if f.scalesearch != string(f.searchEdit.Buffer) {
 			f.scalesearch = string(f.searchEdit.Buffer)
 			f.sclist = f.FilterScaleChords(f.scalesearch)
+			f.symbol, err = theory.ParseChordSymbol(f.scalesearch)
+			f.symbolOK = err == nil
 		}
 		if a&nucular.EditCommitted != 0 {
-			if len(f.sclist) > 0 {
-				f.scale = f.sclist[0]
-			} else {
-				f.scale = f.scalechords[0]
+			switch {
+			case f.symbolOK:
+				f.selectSymbol(f.symbol)
+			case len(f.sclist) > 0:
+				f.selectScaleChord(f.sclist[0])
+			case strings.TrimSpace(f.scalesearch) != "":
+				// Tell why the search is not a chord symbol
+				_, err = theory.ParseChordSymbol(f.scalesearch)
+				f.error = fmt.Sprintf("Error: %v", err)
+			default:
+				f.selectScaleChord(f.scalechords[0])
 			}
-			f.saveState.ScaleChord = f.scale
-			f.setDirty()
 			w.Close()
 		}
 
+		if f.symbolOK {
+			if w.MenuItem(label.TA("Symbol: "+f.symbol.String(), "LC")) {
+				f.selectSymbol(f.symbol)
+			}
+		}
 		for i := range f.sclist {
 			if w.MenuItem(label.TA(f.sclist[i], "LC")) {
-				ret := strings.Replace(f.sclist[i], "Scale: ", "", 1)
-				f.isScale = (ret != f.sclist[i])
-
-				ret = strings.Replace(ret, "Chord: ", "", 1)
-				f.scale = ret
-				f.saveState.ScaleChord = f.scale
-				f.setDirty()
+				f.selectScaleChord(f.sclist[i])
 			}
 		}
 	}
 
-	var err error
 	a := f.tuningEdit.Edit(w)
 	if a&nucular.EditCommitted != 0 {
 		f.tuning, err = parseTuning(string(f.tuningEdit.Buffer))
@@ -429,12 +1971,16 @@ func (f *FretUI) update(w *nucular.Window) {
 			f.error = fmt.Sprintf("Error: %v", err)
 		} else {
 			f.error = ""
-			f.tuningEdit.Buffer = []rune(strings.Join(f.tuning, ""))
+			f.tuningEdit.Buffer = []rune(formatTuning(f.tuning))
+			f.setPreset(presetOf(f.tuning))
 		}
 	}
 
+	f.presetCombo(w)
+
 	if f.newFretBoard != nil {
-		err = f.AddFretBoard(f.newFretBoard.Tuning, f.newFretBoard.Root, f.newFretBoard.Scale, f.newFretBoard.IsScale)
+		err = f.AddFretBoard(f.newFretBoard.Tuning, f.newFretBoard.Root, f.newFretBoard.Scale, "",
+			f.newFretBoard.IsScale, f.newFretBoard.Capo)
 		if err != nil {
 			f.error = fmt.Sprintf("Error: %v", err)
 		}
@@ -446,10 +1992,10 @@ func (f *FretUI) update(w *nucular.Window) {
 		if err != nil {
 			f.error = fmt.Sprintf("Error: %v", err)
 		} else {
-			f.saveState.Tuning = strings.Join(f.tuning, "")
+			f.saveState.Tuning = formatTuning(f.tuning)
 			f.setDirty()
 
-			err = f.AddFretBoard(f.tuning, f.root, f.scale, f.isScale)
+			err = f.AddFretBoard(f.tuning, f.root, f.scale, f.bass, f.isScale, 0)
 			if err != nil {
 				f.error = fmt.Sprintf("Error: %v", err)
 			}
@@ -464,17 +2010,17 @@ func (f *FretUI) update(w *nucular.Window) {
 				f.error = fmt.Sprintf("Error: %v", err)
 			} else {
 				f.error = ""
-				ib, err := addChordListBoard(f.tuning, f.root, f.scale)
+				ib, err := addChordListBoard(f.tuning, f.root, f.scale, 0)
 				if err != nil {
 					f.error = fmt.Sprintf("Error: %v", err)
 				} else {
 					f.boards = append(f.boards, *ib)
-					f.saveState.Tuning = strings.Join(f.tuning, "")
+					f.saveState.Tuning = formatTuning(f.tuning)
 					f.saveState.Boards = append(f.saveState.Boards, BoardState{
 						Name:   f.scale,
 						Type:   TypeList,
 						Root:   f.root,
-						Tuning: strings.Join(f.tuning, ""),
+						Tuning: formatTuning(f.tuning),
 					})
 					f.setDirty()
 				}
@@ -482,10 +2028,115 @@ func (f *FretUI) update(w *nucular.Window) {
 		}
 	}
 
+	if w.Button(label.T("Voicings"), false) {
+		if f.isScale {
+			f.error = fmt.Sprintf("Given chord is not a chord: %s", f.scale)
+		} else {
+			f.tuning, err = parseTuning(string(f.tuningEdit.Buffer))
+			if err != nil {
+				f.error = fmt.Sprintf("Error: %v", err)
+			} else {
+				f.error = ""
+				f.saveState.Tuning = formatTuning(f.tuning)
+				err = f.addBoardState(BoardState{
+					Name:       f.scale,
+					Type:       TypeVoicing,
+					Root:       f.root,
+					Tuning:     formatTuning(f.tuning),
+					RootInBass: fretboard.DefaultVoicingOptions.RootInBass,
+					Bass:       f.bass,
+				})
+				if err != nil {
+					f.error = fmt.Sprintf("Error: %v", err)
+				}
+			}
+		}
+	}
+
+	if w.Button(label.T("Identify"), false) {
+		f.tuning, err = parseTuning(string(f.tuningEdit.Buffer))
+		if err != nil {
+			f.error = fmt.Sprintf("Error: %v", err)
+		} else {
+			f.error = ""
+			f.saveState.Tuning = formatTuning(f.tuning)
+			err = f.addBoardState(BoardState{
+				Type:   TypeIdentify,
+				Tuning: formatTuning(f.tuning),
+			})
+			if err != nil {
+				f.error = fmt.Sprintf("Error: %v", err)
+			}
+		}
+	}
+
+	if w.Button(label.T("Custom"), false) {
+		f.tuning, err = parseTuning(string(f.tuningEdit.Buffer))
+		if err != nil {
+			f.error = fmt.Sprintf("Error: %v", err)
+		} else {
+			f.error = ""
+			f.saveState.Tuning = formatTuning(f.tuning)
+			err = f.addBoardState(BoardState{
+				Name:   customTitle,
+				Type:   TypeCustom,
+				Tuning: formatTuning(f.tuning),
+				Frets:  defaultFrets,
+			})
+			if err != nil {
+				f.error = fmt.Sprintf("Error: %v", err)
+			}
+		}
+	}
+
+	if w.Button(label.T("Find scales"), false) {
+		// Start searching with the notes of the current scale or chord
+		var notes []string
+		if f.isScale {
+			notes, err = theory.GetScale(f.root, f.scale)
+		} else {
+			notes, err = theory.GetChord(f.root, f.scale)
+		}
+		if err != nil {
+			f.error = fmt.Sprintf("Error: %v", err)
+		} else {
+			f.error = ""
+			err = f.addBoardState(BoardState{
+				Type:   TypeFind,
+				Tuning: formatTuning(f.tuning),
+				Notes:  strings.Join(notes, " "),
+			})
+			if err != nil {
+				f.error = fmt.Sprintf("Error: %v", err)
+			}
+		}
+	}
+
 	w.PropertyInt("", 1, &f.columns, 5, 1, 1)
 
-	w.Row(30).Dynamic(1)
+	w.Row(30).Ratio(0.56, 0.14, 0.08, 0.08, 0.14)
 	w.Label(f.error, "LC")
+	w.Label("Transpose all:", "RC")
+	if w.Button(label.T("-"), false) {
+		f.transposeAll(-1, "")
+	}
+	if w.Button(label.T("+"), false) {
+		f.transposeAll(1, "")
+	}
+	if w := w.Combo(label.T("To key"), 400, nil); w != nil {
+		w.Row(30).Dynamic(1)
+		for i := range theory.RootNotes {
+			if w.MenuItem(label.TA(theory.RootNotes[i], "LC")) {
+				f.saveState.Root = f.root
+				steps, key, err := transposeSteps(f.root, theory.RootNotes[i])
+				if err != nil {
+					f.error = fmt.Sprintf("Error: %v", err)
+				} else {
+					f.transposeAll(steps, key)
+				}
+			}
+		}
+	}
 
 	if f.columns != f.saveState.Columns {
 		f.saveState.Columns = f.columns
@@ -498,9 +2149,14 @@ func (f *FretUI) update(w *nucular.Window) {
 			w.Row(700).Dynamic(f.columns)
 		}
 		var di int
-		if f.boards[i].Type == TypeList {
+		switch f.boards[i].Type {
+		case TypeList:
 			di = f.ChordListWidget(w, f.boards[i].Name, i)
-		} else {
+		case TypeFind:
+			di = f.FindScalesWidget(w, f.boards[i].Name, i)
+		case TypeProgression:
+			di = f.ProgressionWidget(w, f.boards[i].Name, i)
+		default:
 			di = f.FretWidget(w, f.boards[i].Name, i)
 		}
 		if di >= 0 {
@@ -545,33 +2201,42 @@ func NewFretUI() *FretUI {
 
 	fu.tuningEdit.Flags = nucular.EditField
 	fu.tuningEdit.Maxlen = 64
-	fu.tuningEdit.Buffer = []rune(strings.Join(fu.tuning, ""))
+	fu.tuningEdit.Buffer = []rune(formatTuning(fu.tuning))
+	fu.preset = presetOf(fu.tuning)
+
+	err := LoadDefinitions()
+	if err != nil {
+		fu.error = fmt.Sprintf("Error in definitions: %v", err)
+	}
 
-	fu.scalechords = make([]string, 0, len(Scales)+len(Chords))
-	for s := range Scales {
+	fu.scalechords = make([]string, 0, len(theory.Scales)+len(theory.Chords))
+	for s := range theory.Scales {
 		fu.scalechords = append(fu.scalechords, "Scale: "+s)
 	}
 
-	for c := range Chords {
+	for c := range theory.Chords {
 		fu.scalechords = append(fu.scalechords, "Chord: "+c)
 	}
 	fu.sclist = fu.scalechords
 
 	ss, err := Load()
 	if err == nil {
+		if path, err := getSaveFilePath(); err == nil {
+			fmt.Println("Loaded configuration from:", path)
+		}
 		fu.saveState = *ss
-		for i := range Notes {
-			if ss.Root == Notes[i] {
-				fu.root = ss.Root
-			}
+		if root, err := theory.NormalizeNote(ss.Root); err == nil {
+			fu.root = root
 		}
 
-		if _, ok := Scales[ss.ScaleChord]; ok {
+		defineSymbolChord(ss.ScaleChord)
+		if _, ok := theory.Scales[ss.ScaleChord]; ok {
 			fu.scale = ss.ScaleChord
 			fu.isScale = true
-		} else if _, ok := Chords[ss.ScaleChord]; ok {
-			fu.scale = ss.ScaleChord
+		} else if name, ok := theory.ChordName(ss.ScaleChord); ok {
+			fu.scale = name
 			fu.isScale = false
+			fu.bass = ss.Bass
 		}
 
 		if ss.Width != 0 {
@@ -586,35 +2251,31 @@ func NewFretUI() *FretUI {
 		tuning, err = parseTuning(ss.Tuning)
 		if err == nil {
 			fu.tuning = tuning
-			fu.tuningEdit.Buffer = []rune(strings.Join(fu.tuning, ""))
+			fu.tuningEdit.Buffer = []rune(formatTuning(fu.tuning))
+			fu.preset = ss.Preset
 		}
 
-		for i := range ss.Boards {
-			tuning, err = parseTuning(ss.Boards[i].Tuning)
+		// Only keep the boards that could be restored
+		fu.saveState.Boards = nil
+		for _, bs := range ss.Boards {
+			if (bs.Type == TypeScale || bs.Type == TypeChord) && bs.Frets == 0 {
+				bs.Frets = defaultFrets
+			}
+			if bs.Type == TypeChord || bs.Type == TypeVoicing {
+				defineSymbolChord(bs.Name)
+			}
+			if bs.Overlay != nil && !bs.Overlay.IsScale {
+				defineSymbolChord(bs.Overlay.Name)
+			}
+			if bs.Base != nil && !bs.Base.IsScale {
+				defineSymbolChord(bs.Base.Name)
+			}
+			ib, err := boardFromState(bs)
 			if err != nil {
 				continue
 			}
-
-			tp := ss.Boards[i].Type
-			if tp == TypeScale || tp == TypeChord {
-				isscale := true
-				if tp == TypeChord {
-					isscale = false
-				}
-				var fb *FretBoard
-				fb, err = addBoard(tuning, ss.Boards[i].Root, ss.Boards[i].Name, isscale)
-				if err == nil {
-					var ib infoBoard
-					ib.FretBoard = *fb
-					fu.boards = append(fu.boards, ib)
-				}
-			} else {
-				ib, err := addChordListBoard(tuning, ss.Boards[i].Root, ss.Boards[i].Name)
-				if err == nil {
-					fu.boards = append(fu.boards, *ib)
-				}
-			}
-
+			fu.boards = append(fu.boards, *ib)
+			fu.saveState.Boards = append(fu.saveState.Boards, bs)
 		}
 	}
 
@@ -630,9 +2291,25 @@ func (f *FretUI) FilterScaleChords(filter string) []string {
 		return f.scalechords
 	}
 
+	// The chords are also found by their aliases, e.g. m7b5
+	matches := func(item string) bool {
+		if re.FindStringIndex(item) != nil {
+			return true
+		}
+		if !strings.HasPrefix(item, "Chord: ") {
+			return false
+		}
+		for _, alias := range theory.Aliases(strings.TrimPrefix(item, "Chord: ")) {
+			if re.FindStringIndex(alias) != nil {
+				return true
+			}
+		}
+		return false
+	}
+
 	var ret []string
 	for i := range f.scalechords {
-		if re.FindStringIndex(f.scalechords[i]) != nil {
+		if matches(f.scalechords[i]) {
 			ret = append(ret, f.scalechords[i])
 		}
 	}
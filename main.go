@@ -22,6 +22,12 @@ func fault(err error, message string, arg ...string) {
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		err := CLIMain(os.Stdout, os.Args[1:])
+		fault(err, "Running command failed")
+		os.Exit(0)
+	}
+
 	err := GUIMain(progVersion)
 	fault(err, "Running GUI failed")
 
@@ -0,0 +1,185 @@
+// Package midi writes Standard MIDI Files.
+package midi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// DefaultDivision is the amount of ticks in a quarter note
+const DefaultDivision = 480
+
+// Note is a note played at the given time in ticks
+type Note struct {
+	// MIDI note number, 60 is the middle C
+	Key int
+
+	// Loudness between 1 and 127
+	Velocity int
+
+	Start    int
+	Duration int
+}
+
+// File is a single track MIDI file
+type File struct {
+	// Name of the track
+	Name string
+
+	// Ticks in a quarter note
+	Division int
+
+	// Quarter notes per minute
+	Tempo int
+
+	// General MIDI program of the instrument, e.g. 24 is the nylon string
+	// guitar
+	Program int
+
+	Notes []Note
+}
+
+// NewFile creates a file with the default division and an acoustic guitar
+// instrument
+func NewFile(name string, tempo int) *File {
+	return &File{
+		Name:     name,
+		Division: DefaultDivision,
+		Tempo:    tempo,
+		Program:  24,
+	}
+}
+
+// Add appends notes that start at the same time
+func (f *File) Add(keys []int, start, duration, velocity int) {
+	for _, k := range keys {
+		f.Notes = append(f.Notes, Note{
+			Key:      k,
+			Velocity: velocity,
+			Start:    start,
+			Duration: duration,
+		})
+	}
+}
+
+// Length returns the end time of the last note in ticks
+func (f *File) Length() int {
+	ret := 0
+	for _, n := range f.Notes {
+		if n.Start+n.Duration > ret {
+			ret = n.Start + n.Duration
+		}
+	}
+	return ret
+}
+
+type event struct {
+	time int
+	data []byte
+}
+
+// Write the variable length quantity used for delta times and lengths
+func writeVarLen(buf *bytes.Buffer, value int) {
+	var tmp [4]byte
+	i := len(tmp) - 1
+	tmp[i] = byte(value & 0x7f)
+	for value >>= 7; value > 0; value >>= 7 {
+		i--
+		tmp[i] = byte(value&0x7f) | 0x80
+	}
+	buf.Write(tmp[i:])
+}
+
+func (f *File) validate() error {
+	if f.Division <= 0 || f.Division > 0x7fff {
+		return fmt.Errorf("invalid division %d", f.Division)
+	}
+	if f.Tempo <= 0 {
+		return fmt.Errorf("invalid tempo %d", f.Tempo)
+	}
+	if f.Program < 0 || f.Program > 127 {
+		return fmt.Errorf("invalid program %d", f.Program)
+	}
+	for _, n := range f.Notes {
+		switch {
+		case n.Key < 0 || n.Key > 127:
+			return fmt.Errorf("invalid note key %d", n.Key)
+		case n.Velocity < 1 || n.Velocity > 127:
+			return fmt.Errorf("invalid note velocity %d", n.Velocity)
+		case n.Start < 0 || n.Duration <= 0:
+			return fmt.Errorf("invalid note time %d+%d", n.Start, n.Duration)
+		}
+	}
+	return nil
+}
+
+// Get the track data without the chunk header
+func (f *File) track() []byte {
+	var buf bytes.Buffer
+
+	meta := func(typ byte, data []byte) {
+		buf.WriteByte(0)
+		buf.Write([]byte{0xff, typ})
+		writeVarLen(&buf, len(data))
+		buf.Write(data)
+	}
+
+	meta(0x03, []byte(f.Name))
+	usec := 60000000 / f.Tempo
+	meta(0x51, []byte{byte(usec >> 16), byte(usec >> 8), byte(usec)})
+	buf.Write([]byte{0, 0xc0, byte(f.Program)})
+
+	var events []event
+	for _, n := range f.Notes {
+		events = append(events,
+			event{n.Start, []byte{0x90, byte(n.Key), byte(n.Velocity)}},
+			event{n.Start + n.Duration, []byte{0x80, byte(n.Key), 0}})
+	}
+	// Stop the notes before starting new ones at the same time
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].time != events[j].time {
+			return events[i].time < events[j].time
+		}
+		return events[i].data[0] < events[j].data[0]
+	})
+
+	now := 0
+	for _, e := range events {
+		writeVarLen(&buf, e.time-now)
+		buf.Write(e.data)
+		now = e.time
+	}
+
+	meta(0x2f, nil)
+	return buf.Bytes()
+}
+
+// Write writes the file in the Standard MIDI File format 0
+func (f *File) Write(w io.Writer) error {
+	err := f.validate()
+	if err != nil {
+		return err
+	}
+
+	track := f.track()
+	chunks := []interface{}{
+		[]byte("MThd"),
+		uint32(6),
+		uint16(0), // Format
+		uint16(1), // Tracks
+		uint16(f.Division),
+		[]byte("MTrk"),
+		uint32(len(track)),
+		track,
+	}
+	for _, v := range chunks {
+		err := binary.Write(w, binary.BigEndian, v)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package midi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// parsedEvent is an event of a parsed track at an absolute time in ticks
+type parsedEvent struct {
+	time int
+	data []byte
+}
+
+type parsedFile struct {
+	format   int
+	tracks   int
+	division int
+	events   []parsedEvent
+}
+
+// Read a variable length quantity
+func readVarLen(data []byte) (int, int, error) {
+	ret := 0
+	for i := 0; i < 4 && i < len(data); i++ {
+		ret = ret<<7 | int(data[i]&0x7f)
+		if data[i]&0x80 == 0 {
+			return ret, i + 1, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("invalid variable length quantity")
+}
+
+// Parse a single track Standard MIDI File. Running status is not
+// supported as the writer doesn't use it.
+func parseFile(data []byte) (*parsedFile, error) {
+	if len(data) < 14 || string(data[:4]) != "MThd" {
+		return nil, fmt.Errorf("no MThd header")
+	}
+	if binary.BigEndian.Uint32(data[4:8]) != 6 {
+		return nil, fmt.Errorf("invalid MThd length")
+	}
+	ret := &parsedFile{
+		format:   int(binary.BigEndian.Uint16(data[8:10])),
+		tracks:   int(binary.BigEndian.Uint16(data[10:12])),
+		division: int(binary.BigEndian.Uint16(data[12:14])),
+	}
+
+	data = data[14:]
+	if len(data) < 8 || string(data[:4]) != "MTrk" {
+		return nil, fmt.Errorf("no MTrk header")
+	}
+	length := int(binary.BigEndian.Uint32(data[4:8]))
+	data = data[8:]
+	if length != len(data) {
+		return nil, fmt.Errorf("MTrk length %d, got %d bytes", length, len(data))
+	}
+
+	now := 0
+	for len(data) > 0 {
+		delta, n, err := readVarLen(data)
+		if err != nil {
+			return nil, err
+		}
+		now += delta
+		data = data[n:]
+		if len(data) == 0 {
+			return nil, fmt.Errorf("missing event")
+		}
+
+		size := 0
+		switch status := data[0]; {
+		case status == 0xff:
+			l, n, err := readVarLen(data[2:])
+			if err != nil {
+				return nil, err
+			}
+			size = 2 + n + l
+		case status&0xf0 == 0xc0:
+			size = 2
+		case status&0xf0 == 0x80 || status&0xf0 == 0x90:
+			size = 3
+		default:
+			return nil, fmt.Errorf("unsupported status 0x%02x", status)
+		}
+		if size > len(data) {
+			return nil, fmt.Errorf("truncated event")
+		}
+		ret.events = append(ret.events, parsedEvent{now, data[:size]})
+		data = data[size:]
+	}
+	return ret, nil
+}
+
+func TestWriteVarLen(t *testing.T) {
+	tests := []struct {
+		value int
+		want  []byte
+	}{
+		{0, []byte{0x00}},
+		{0x40, []byte{0x40}},
+		{0x7f, []byte{0x7f}},
+		{0x80, []byte{0x81, 0x00}},
+		{0x2000, []byte{0xc0, 0x00}},
+		{0x3fff, []byte{0xff, 0x7f}},
+		{0x4000, []byte{0x81, 0x80, 0x00}},
+		{0x0fffffff, []byte{0xff, 0xff, 0xff, 0x7f}},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		writeVarLen(&buf, tt.value)
+		if !bytes.Equal(buf.Bytes(), tt.want) {
+			t.Errorf("writeVarLen(0x%x) = % x, want % x", tt.value, buf.Bytes(), tt.want)
+		}
+		got, _, err := readVarLen(buf.Bytes())
+		if err != nil || got != tt.value {
+			t.Errorf("readVarLen(% x) = 0x%x, %v, want 0x%x", buf.Bytes(), got, err, tt.value)
+		}
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	f := NewFile("C Major", 120)
+	f.Add([]int{60, 64, 67}, 0, 480, 100)
+	f.Add([]int{62}, 480, 240, 90)
+
+	var buf bytes.Buffer
+	err := f.Write(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := parseFile(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.format != 0 || p.tracks != 1 || p.division != DefaultDivision {
+		t.Errorf("header format %d tracks %d division %d, want 0 1 %d",
+			p.format, p.tracks, p.division, DefaultDivision)
+	}
+
+	// 120 quarter notes per minute is 500000 microseconds per quarter note
+	want := []parsedEvent{
+		{0, append([]byte{0xff, 0x03, 7}, "C Major"...)},
+		{0, []byte{0xff, 0x51, 3, 0x07, 0xa1, 0x20}},
+		{0, []byte{0xc0, 24}},
+		{0, []byte{0x90, 60, 100}},
+		{0, []byte{0x90, 64, 100}},
+		{0, []byte{0x90, 67, 100}},
+		// The notes are stopped before the next ones start
+		{480, []byte{0x80, 60, 0}},
+		{480, []byte{0x80, 64, 0}},
+		{480, []byte{0x80, 67, 0}},
+		{480, []byte{0x90, 62, 90}},
+		{720, []byte{0x80, 62, 0}},
+		{720, []byte{0xff, 0x2f, 0}},
+	}
+	if !reflect.DeepEqual(p.events, want) {
+		t.Errorf("events\n%v\nwant\n%v", p.events, want)
+	}
+}
+
+func TestWriteInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		f    File
+	}{
+		{"division", File{Division: 0, Tempo: 120}},
+		{"tempo", File{Division: DefaultDivision, Tempo: 0}},
+		{"program", File{Division: DefaultDivision, Tempo: 120, Program: 128}},
+		{"key", File{Division: DefaultDivision, Tempo: 120,
+			Notes: []Note{{Key: 128, Velocity: 100, Duration: 1}}}},
+		{"velocity", File{Division: DefaultDivision, Tempo: 120,
+			Notes: []Note{{Key: 60, Velocity: 0, Duration: 1}}}},
+		{"duration", File{Division: DefaultDivision, Tempo: 120,
+			Notes: []Note{{Key: 60, Velocity: 100}}}},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := tt.f.Write(&buf); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("%s: wrote %d bytes of an invalid file", tt.name, buf.Len())
+		}
+	}
+}
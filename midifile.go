@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kopoli/fretnoter/midi"
+	"github.com/kopoli/fretnoter/theory"
+)
+
+const (
+	defaultTempo      = 120
+	defaultNoteLength = "quarter"
+	midiVelocity      = 90
+)
+
+// Note lengths in quarter notes times four
+var noteLengths = map[string]int{
+	"whole":     16,
+	"half":      8,
+	"quarter":   4,
+	"eighth":    2,
+	"sixteenth": 1,
+}
+
+// Get the note length in ticks of the file
+func noteLengthTicks(f *midi.File, length string) (int, error) {
+	l, ok := noteLengths[length]
+	if !ok {
+		names := make([]string, 0, len(noteLengths))
+		for k := range noteLengths {
+			names = append(names, k)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			return noteLengths[names[i]] > noteLengths[names[j]]
+		})
+		return 0, fmt.Errorf("unknown note length '%s', use one of: %s", length,
+			strings.Join(names, ", "))
+	}
+	return f.Division * l / 4, nil
+}
+
+// Get a MIDI file of the scale played up or the chord played once
+func scaleChordMIDI(tuning []string, root, scale, bass string, isScale bool, tempo int, length string) (*midi.File, error) {
+	var notes []string
+	var err error
+	if isScale {
+		notes, err = theory.GetScale(root, scale)
+	} else {
+		notes, err = theory.GetChord(root, scale)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ret := midi.NewFile(notes[0]+" "+scale+slashName(bass), tempo)
+	ticks, err := noteLengthTicks(ret, length)
+	if err != nil {
+		return nil, err
+	}
+
+	if isScale {
+		pitches, err := scalePitches(tuning, notes)
+		if err != nil {
+			return nil, err
+		}
+		for i := range pitches {
+			ret.Add(pitches[i:i+1], i*ticks, ticks, midiVelocity)
+		}
+	} else {
+		pitches, err := chordPitches(tuning, root, scale, bass)
+		if err != nil {
+			return nil, err
+		}
+		ret.Add(pitches, 0, ticks, midiVelocity)
+	}
+	return ret, nil
+}
+
+// Get a MIDI file of each chord of the chord list board played in order
+func chordListMIDI(ib *infoBoard, tempo int, length string) (*midi.File, error) {
+	ret := midi.NewFile(ib.ScaleNotes[0]+" "+ib.Scale+" chords", tempo)
+	ticks, err := noteLengthTicks(ret, length)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, note := range ib.ScaleNotes {
+		for _, chord := range ib.Chords[note] {
+			pitches, err := chordPitches(ib.Tuning, note, chord, "")
+			if err != nil {
+				return nil, err
+			}
+			ret.Add(pitches, ret.Length(), ticks, midiVelocity)
+		}
+	}
+	return ret, nil
+}
+
+// Get a MIDI file of the chords of the progression board played in order
+func progressionMIDI(ib *infoBoard, tempo int, length string) (*midi.File, error) {
+	ret := midi.NewFile(ib.Root+" "+ib.Scale+" progression", tempo)
+	ticks, err := noteLengthTicks(ret, length)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range ib.ChordBoards {
+		pitches, err := boardPitches(&ib.ChordBoards[i])
+		if err != nil {
+			return nil, err
+		}
+		ret.Add(pitches, ret.Length(), ticks, midiVelocity)
+	}
+	return ret, nil
+}
+
+func writeMIDIFile(path string, f *midi.File) error {
+	fp, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = f.Write(fp)
+	if err != nil {
+		fp.Close()
+		return err
+	}
+	return fp.Close()
+}
@@ -6,21 +6,93 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/OpenPeeDeeP/xdg"
+	"github.com/kopoli/fretnoter/theory"
 )
 
 const (
 	TypeScale = "Scale"
 	TypeChord = "Chord"
 	TypeList  = "List"
+
+	TypeVoicing  = "Voicing"
+	TypeIdentify = "Identify"
+	TypeFind     = "FindScales"
+
+	TypeProgression = "Progression"
+	TypeCustom      = "Custom"
+	TypeOverlay     = "Overlay"
 )
 
+// OverlayLayer is a scale or a chord of an overlay board
+type OverlayLayer struct {
+	Root    string
+	Name    string
+	IsScale bool
+}
+
+// CustomNote is a note drawn on a custom board. The type is one of
+// fretboard.NoteTypes.
+type CustomNote struct {
+	String int
+	Fret   int
+	Type   string
+}
+
 type BoardState struct {
 	Name   string
 	Type   string
 	Root   string
 	Tuning string
+
+	// Labels of the notes: "Intervals", "Degrees", "Shapes" or the note
+	// names if empty
+	Labels string `json:",omitempty"`
+
+	// Orientation of the board
+	Horizontal bool `json:",omitempty"`
+	LeftHanded bool `json:",omitempty"`
+
+	// Fret of the capo
+	Capo int `json:",omitempty"`
+
+	// Shown fret range of scale and chord boards
+	StartingFret int `json:",omitempty"`
+	Frets        int `json:",omitempty"`
+
+	// Position system and the selected position of scale boards
+	Positions string `json:",omitempty"`
+	Position  int    `json:",omitempty"`
+
+	// Bass note of slash chords and inversions on chord and voicing boards
+	Bass string `json:",omitempty"`
+
+	// Selected voicing and its constraints of voicing boards. The
+	// constraints use the defaults if they are not set.
+	Voicing    int  `json:",omitempty"`
+	MaxSpan    int  `json:",omitempty"`
+	Fingers    int  `json:",omitempty"`
+	MaxMuted   *int `json:",omitempty"`
+	RootInBass bool `json:",omitempty"`
+
+	// Fretted shape of chord identification boards, e.g. "x32010"
+	Shape string `json:",omitempty"`
+
+	// Searched notes of scale search boards
+	Notes string `json:",omitempty"`
+
+	// Roman numerals of progression boards, e.g. "I-vi-IV-V"
+	Progression string `json:",omitempty"`
+
+	// Drawn notes of custom boards
+	CustomNotes []CustomNote `json:",omitempty"`
+
+	// The scale or chord of overlay boards and the one drawn over it
+	Base    *OverlayLayer `json:",omitempty"`
+	Overlay *OverlayLayer `json:",omitempty"`
 }
 
 type State struct {
@@ -28,22 +100,62 @@ type State struct {
 	ScaleChord string
 	Columns    int
 	Tuning     string
-	Width      int
-	Height     int
+
+	// Name of the selected instrument preset, empty for a custom tuning
+	Preset string `json:",omitempty"`
+	Width  int
+	Height int
+
+	// Bass note of the selected slash chord
+	Bass string `json:",omitempty"`
 
 	Boards []BoardState
 }
 
-func getSaveFilePath() (string, error) {
+func getSaveDir() (string, error) {
 	path := xdg.New("", "fretnoter").DataHome()
 	err := os.MkdirAll(path, 0755)
 	if err != nil {
 		return "", err
 	}
 
+	return path, nil
+}
+
+func getSaveFilePath() (string, error) {
+	path, err := getSaveDir()
+	if err != nil {
+		return "", err
+	}
+
 	return filepath.Join(path, "config.json"), nil
 }
 
+func getDefinitionsFilePath() (string, error) {
+	path, err := getSaveDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(path, "definitions.json"), nil
+}
+
+// Directory where the boards are exported from the GUI
+func getExportDir() (string, error) {
+	path, err := getSaveDir()
+	if err != nil {
+		return "", err
+	}
+
+	path = filepath.Join(path, "export")
+	err = os.MkdirAll(path, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
 func Save(s *State) error {
 	data, err := json.Marshal(*s)
 	if err != nil {
@@ -74,7 +186,66 @@ func Load() (*State, error) {
 		return nil, err
 	}
 
-	fmt.Println("Loaded configuration from:", path)
-
 	return &ret, nil
 }
+
+// Definitions are the user defined scales and chords
+type Definitions struct {
+	// Scales as steps from the previous note
+	Scales map[string][]int
+
+	// Chords as the distance from the root note
+	Chords map[string][]int
+}
+
+func sortedKeys(m map[string][]int) []string {
+	ret := make([]string, 0, len(m))
+	for k := range m {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// LoadDefinitions adds the user defined scales and chords to the known
+// ones. The valid definitions are added even if some are invalid. It is not
+// an error if the definitions file doesn't exist.
+func LoadDefinitions() error {
+	path, err := getDefinitionsFilePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var defs Definitions
+	err = json.Unmarshal(data, &defs)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	var errs []string
+	for _, name := range sortedKeys(defs.Scales) {
+		err = theory.AddScale(name, defs.Scales[name])
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	for _, name := range sortedKeys(defs.Chords) {
+		err = theory.AddChord(name, defs.Chords[name])
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s: %s", path, strings.Join(errs, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+// Package synth renders plucked string sounds to WAV files.
+package synth
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"math/rand"
+)
+
+// SampleRate of the rendered audio in Hz
+const SampleRate = 44100
+
+// Decay of the plucked string per sample period
+const decay = 0.996
+
+// Event is a note played at the given time
+type Event struct {
+	// MIDI note number, 69 is A4
+	Note int
+
+	// Start time and the duration in seconds
+	Start    float64
+	Duration float64
+
+	// Loudness between 0 and 1
+	Velocity float64
+}
+
+// Frequency returns the frequency of the MIDI note in Hz
+func Frequency(note int) float64 {
+	return 440.0 * math.Pow(2, float64(note-69)/12.0)
+}
+
+// Pluck renders a plucked string with the Karplus-Strong algorithm. The
+// noise is seeded with seed so the result is reproducible.
+func Pluck(freq, duration float64, seed int64) []float64 {
+	samples := int(duration * SampleRate)
+	period := int(SampleRate/freq + 0.5)
+	if period < 2 {
+		period = 2
+	}
+
+	rnd := rand.New(rand.NewSource(seed))
+	buf := make([]float64, period)
+	for i := range buf {
+		buf[i] = rnd.Float64()*2 - 1
+	}
+
+	ret := make([]float64, samples)
+	for i := range ret {
+		pos := i % period
+		ret[i] = buf[pos]
+		buf[pos] = decay * 0.5 * (buf[pos] + buf[(pos+1)%period])
+	}
+
+	// Fade out the end to avoid clicks
+	fade := SampleRate / 100
+	if fade > samples {
+		fade = samples
+	}
+	for i := 0; i < fade; i++ {
+		ret[samples-1-i] *= float64(i) / float64(fade)
+	}
+
+	return ret
+}
+
+// Render mixes the events to mono samples between -1 and 1
+func Render(events []Event) []float64 {
+	length := 0.0
+	for _, e := range events {
+		if e.Start+e.Duration > length {
+			length = e.Start + e.Duration
+		}
+	}
+
+	ret := make([]float64, int(length*SampleRate)+1)
+	for i, e := range events {
+		start := int(e.Start * SampleRate)
+		for j, v := range Pluck(Frequency(e.Note), e.Duration, int64(i+1)) {
+			ret[start+j] += v * e.Velocity
+		}
+	}
+
+	peak := 0.0
+	for _, v := range ret {
+		peak = math.Max(peak, math.Abs(v))
+	}
+	if peak > 0.9 {
+		for i := range ret {
+			ret[i] *= 0.9 / peak
+		}
+	}
+
+	return ret
+}
+
+// WriteWAV writes the samples as a 16-bit mono PCM WAV file
+func WriteWAV(w io.Writer, samples []float64) error {
+	const (
+		channels      = 1
+		bitsPerSample = 16
+	)
+	dataSize := uint32(len(samples) * channels * bitsPerSample / 8)
+
+	header := []interface{}{
+		[]byte("RIFF"),
+		uint32(36 + dataSize),
+		[]byte("WAVE"),
+		[]byte("fmt "),
+		uint32(16), // Size of the fmt chunk
+		uint16(1),  // PCM
+		uint16(channels),
+		uint32(SampleRate),
+		uint32(SampleRate * channels * bitsPerSample / 8),
+		uint16(channels * bitsPerSample / 8),
+		uint16(bitsPerSample),
+		[]byte("data"),
+		dataSize,
+	}
+	for _, v := range header {
+		err := binary.Write(w, binary.LittleEndian, v)
+		if err != nil {
+			return err
+		}
+	}
+
+	data := make([]int16, len(samples))
+	for i, v := range samples {
+		v = math.Max(-1, math.Min(1, v))
+		data[i] = int16(v * math.MaxInt16)
+	}
+	return binary.Write(w, binary.LittleEndian, data)
+}
@@ -0,0 +1,122 @@
+package synth
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestFrequency(t *testing.T) {
+	tests := []struct {
+		note int
+		want float64
+	}{
+		{69, 440},
+		{81, 880},
+		{57, 220},
+		{60, 261.63},
+		{40, 82.41},
+	}
+	for _, tt := range tests {
+		got := Frequency(tt.note)
+		if math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("Frequency(%d) = %.2f, want %.2f", tt.note, got, tt.want)
+		}
+	}
+}
+
+func TestPluck(t *testing.T) {
+	samples := Pluck(Frequency(69), 0.5, 1)
+	if len(samples) != SampleRate/2 {
+		t.Errorf("got %d samples, want %d", len(samples), SampleRate/2)
+	}
+	for i, v := range samples {
+		if v < -1 || v > 1 {
+			t.Fatalf("sample %d is %f", i, v)
+		}
+	}
+	if samples[len(samples)-1] != 0 {
+		t.Errorf("the last sample is %f, want it faded out", samples[len(samples)-1])
+	}
+
+	again := Pluck(Frequency(69), 0.5, 1)
+	for i := range samples {
+		if samples[i] != again[i] {
+			t.Fatalf("sample %d differs with the same seed", i)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	samples := Render([]Event{
+		{Note: 60, Start: 0, Duration: 1, Velocity: 1},
+		{Note: 64, Start: 0.5, Duration: 1.5, Velocity: 1},
+		{Note: 67, Start: 0.5, Duration: 1, Velocity: 1},
+	})
+	if len(samples) != 2*SampleRate+1 {
+		t.Errorf("got %d samples, want %d", len(samples), 2*SampleRate+1)
+	}
+
+	peak := 0.0
+	for _, v := range samples {
+		peak = math.Max(peak, math.Abs(v))
+	}
+	if peak == 0 || peak > 0.9+1e-9 {
+		t.Errorf("the peak is %f, want it above 0 and at most 0.9", peak)
+	}
+
+	if len(Render(nil)) != 1 {
+		t.Errorf("expected a single sample without events")
+	}
+}
+
+func TestWriteWAV(t *testing.T) {
+	samples := []float64{0, 1, -1, 0.5, 2}
+
+	var buf bytes.Buffer
+	err := WriteWAV(&buf, samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if len(data) != 44+2*len(samples) {
+		t.Fatalf("got %d bytes, want %d", len(data), 44+2*len(samples))
+	}
+
+	u16 := func(pos int) int { return int(binary.LittleEndian.Uint16(data[pos:])) }
+	u32 := func(pos int) int { return int(binary.LittleEndian.Uint32(data[pos:])) }
+	tests := []struct {
+		field string
+		got   interface{}
+		want  interface{}
+	}{
+		{"RIFF id", string(data[0:4]), "RIFF"},
+		{"RIFF size", u32(4), len(data) - 8},
+		{"format", string(data[8:12]), "WAVE"},
+		{"fmt id", string(data[12:16]), "fmt "},
+		{"fmt size", u32(16), 16},
+		{"audio format", u16(20), 1},
+		{"channels", u16(22), 1},
+		{"sample rate", u32(24), SampleRate},
+		{"byte rate", u32(28), SampleRate * 2},
+		{"block align", u16(32), 2},
+		{"bits per sample", u16(34), 16},
+		{"data id", string(data[36:40]), "data"},
+		{"data size", u32(40), 2 * len(samples)},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s is %v, want %v", tt.field, tt.got, tt.want)
+		}
+	}
+
+	// The samples are clipped to 16 bits
+	want := []int16{0, math.MaxInt16, -math.MaxInt16, math.MaxInt16 / 2, math.MaxInt16}
+	for i, w := range want {
+		got := int16(binary.LittleEndian.Uint16(data[44+2*i:]))
+		if got != w {
+			t.Errorf("sample %d is %d, want %d", i, got, w)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package theory
+
+// AddScale adds a scale given as steps from the previous note to Scales.
+// The steps must add up to an octave.
+func AddScale(name string, steps []int) error {
+	fail := func(reason string) error {
+		return &InvalidDefinitionError{Kind: "scale", Name: name, Reason: reason}
+	}
+
+	if name == "" {
+		return fail("name is empty")
+	}
+	if _, ok := Scales[name]; ok {
+		return fail("scale already exists")
+	}
+	if len(steps) < 2 {
+		return fail("needs at least 2 steps")
+	}
+
+	sum := 0
+	for _, st := range steps {
+		if st < 1 {
+			return fail("steps must be at least 1")
+		}
+		sum += st
+	}
+	if sum != len(Notes) {
+		return fail("steps must add up to 12")
+	}
+
+	Scales[name] = append([]int{}, steps...)
+	return nil
+}
+
+// AddChord adds a chord given as distances from the root note to Chords.
+// The distances must start from 0 and grow within two octaves.
+func AddChord(name string, distances []int) error {
+	fail := func(reason string) error {
+		return &InvalidDefinitionError{Kind: "chord", Name: name, Reason: reason}
+	}
+
+	if name == "" {
+		return fail("name is empty")
+	}
+	if _, ok := ChordName(name); ok {
+		return fail("chord already exists")
+	}
+	if len(distances) < 2 {
+		return fail("needs at least 2 notes")
+	}
+	if distances[0] != 0 {
+		return fail("the first distance must be 0")
+	}
+	for i := 1; i < len(distances); i++ {
+		if distances[i] <= distances[i-1] {
+			return fail("distances must be in increasing order")
+		}
+		if distances[i] >= 2*len(Notes) {
+			return fail("distances must be less than 24")
+		}
+	}
+
+	Chords[name] = append([]int{}, distances...)
+	return nil
+}
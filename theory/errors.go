@@ -0,0 +1,64 @@
+package theory
+
+import "fmt"
+
+// UnknownNoteError is returned when a note name is not recognized
+type UnknownNoteError struct {
+	Note string
+}
+
+func (e *UnknownNoteError) Error() string {
+	return fmt.Sprintf("note '%s' doesn't exist", e.Note)
+}
+
+// UnknownScaleError is returned when a scale is not in Scales
+type UnknownScaleError struct {
+	Scale string
+}
+
+func (e *UnknownScaleError) Error() string {
+	return fmt.Sprintf("scale '%s' doesn't exist", e.Scale)
+}
+
+// UnknownChordError is returned when a chord is not in Chords
+type UnknownChordError struct {
+	Chord string
+}
+
+func (e *UnknownChordError) Error() string {
+	return fmt.Sprintf("chord '%s' doesn't exist", e.Chord)
+}
+
+// InvalidDefinitionError is returned when a user defined scale or chord is
+// not valid
+type InvalidDefinitionError struct {
+	// "scale" or "chord"
+	Kind   string
+	Name   string
+	Reason string
+}
+
+func (e *InvalidDefinitionError) Error() string {
+	return fmt.Sprintf("invalid %s '%s': %s", e.Kind, e.Name, e.Reason)
+}
+
+// InvalidChordSymbolError is returned when a chord symbol can't be parsed
+type InvalidChordSymbolError struct {
+	Symbol string
+	Reason string
+}
+
+func (e *InvalidChordSymbolError) Error() string {
+	return fmt.Sprintf("invalid chord symbol '%s': %s", e.Symbol, e.Reason)
+}
+
+// InvalidNumeralError is returned when a Roman numeral of a progression
+// can't be parsed
+type InvalidNumeralError struct {
+	Numeral string
+	Reason  string
+}
+
+func (e *InvalidNumeralError) Error() string {
+	return fmt.Sprintf("invalid roman numeral '%s': %s", e.Numeral, e.Reason)
+}
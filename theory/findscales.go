@@ -0,0 +1,129 @@
+package theory
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ScaleMatch is a scale that contains the searched notes
+type ScaleMatch struct {
+	Root  string
+	Scale string
+
+	// The scale has exactly the searched notes
+	Exact bool
+
+	// Notes of the scale that were not searched
+	Extra []string
+}
+
+// Name returns the root and the scale, e.g. "C Major (Ionian)"
+func (m ScaleMatch) Name() string {
+	return m.Root + " " + m.Scale
+}
+
+func (m ScaleMatch) String() string {
+	return m.Name()
+}
+
+// FindScales returns the scales with any root that contain all of the
+// given notes. The root is spelled so that the scale reuses the most of the
+// given note names and then with the fewest accidentals, e.g. Bb instead of
+// A# for "Bb C D Eb F G A". The exact matches are first and the rest are
+// ordered by the amount of extra notes. Then the scales starting from the
+// first given note are first and the rest are ordered by how well the
+// spelling fits the given notes.
+func FindScales(notes []string) ([]ScaleMatch, error) {
+	if len(notes) == 0 {
+		return nil, fmt.Errorf("no notes given")
+	}
+	given := map[string]bool{}
+	roots := append([]string{}, RootNotes...)
+	for i := range notes {
+		n, err := NormalizeNote(notes[i])
+		if err != nil {
+			return nil, err
+		}
+		given[n] = true
+		roots = append(roots, n)
+	}
+	first, _ := NotePosition(notes[0])
+
+	// A match with the amount of notes spelled as given and the amount of
+	// accidentals in the scale
+	type fit struct {
+		match       ScaleMatch
+		tonic       bool
+		spelled     int
+		accidentals int
+	}
+	better := func(a, b fit) bool {
+		if len(a.match.Extra) != len(b.match.Extra) {
+			return len(a.match.Extra) < len(b.match.Extra)
+		}
+		if a.tonic != b.tonic {
+			return a.tonic
+		}
+		if a.spelled != b.spelled {
+			return a.spelled > b.spelled
+		}
+		if a.accidentals != b.accidentals {
+			return a.accidentals < b.accidentals
+		}
+		return a.match.Name() < b.match.Name()
+	}
+
+	var fits []fit
+	for pos := range Notes {
+		for scale := range Scales {
+			var best *fit
+			for _, root := range roots {
+				if p, _ := NotePosition(root); p != pos {
+					continue
+				}
+				scalenotes, err := GetScale(root, scale)
+				if err != nil {
+					return nil, err
+				}
+				if !IsChordInScale(notes, scalenotes) {
+					break
+				}
+
+				f := fit{
+					match: ScaleMatch{
+						Root:  scalenotes[0],
+						Scale: scale,
+					},
+					tonic: pos == first,
+				}
+				for _, sn := range scalenotes {
+					if given[sn] {
+						f.spelled++
+					}
+					if !IsChordInScale([]string{sn}, notes) {
+						f.match.Extra = append(f.match.Extra, sn)
+					}
+					s, _ := parseNote(sn)
+					f.accidentals += accidentalCount([]spelling{s})
+				}
+				f.match.Exact = len(f.match.Extra) == 0
+				if best == nil || better(f, *best) {
+					best = &f
+				}
+			}
+			if best != nil {
+				fits = append(fits, *best)
+			}
+		}
+	}
+
+	sort.Slice(fits, func(i, j int) bool {
+		return better(fits[i], fits[j])
+	})
+
+	ret := make([]ScaleMatch, len(fits))
+	for i := range fits {
+		ret[i] = fits[i].match
+	}
+	return ret, nil
+}
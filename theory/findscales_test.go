@@ -0,0 +1,80 @@
+package theory
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindScales(t *testing.T) {
+	tests := []struct {
+		notes []string
+		want  []string
+	}{
+		// The flat key keeps the flats of the given notes and the scale from
+		// the first note is first
+		{
+			[]string{"Bb", "C", "D", "Eb", "F", "G", "A"},
+			[]string{
+				"Bb Major (Ionian)", "A Locrian Mode", "C Dorian Mode",
+				"D Phrygian Mode", "Eb Lydian Mode", "F Mixolydian Mode",
+				"G Natural Minor (Aeolian)",
+			},
+		},
+		{
+			[]string{"F#", "G#", "A#", "B", "C#", "D#", "E#"},
+			[]string{
+				"F# Major (Ionian)", "A# Phrygian Mode", "B Lydian Mode",
+				"C# Mixolydian Mode", "D# Natural Minor (Aeolian)",
+				"E# Locrian Mode", "G# Dorian Mode",
+			},
+		},
+	}
+	for _, tt := range tests {
+		matches, err := FindScales(tt.notes)
+		if err != nil {
+			t.Errorf("FindScales(%v): %v", tt.notes, err)
+			continue
+		}
+		var got []string
+		for _, m := range matches {
+			if m.Exact {
+				got = append(got, m.Name())
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FindScales(%v) exact matches = %v, want %v", tt.notes, got, tt.want)
+		}
+	}
+}
+
+func TestFindScalesOrder(t *testing.T) {
+	// Gb is both the root of the flat key and a given note
+	matches, err := FindScales([]string{"Gb", "Bb", "Db"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) == 0 || matches[0].Name() != "Gb Pentatonic Major" {
+		t.Fatalf("the first match is %v, want Gb Pentatonic Major", matches)
+	}
+	for i := 1; i < len(matches); i++ {
+		if len(matches[i-1].Extra) > len(matches[i].Extra) {
+			t.Errorf("%s with %d extra notes is after %s with %d",
+				matches[i].Name(), len(matches[i].Extra),
+				matches[i-1].Name(), len(matches[i-1].Extra))
+		}
+	}
+	for _, m := range matches {
+		if m.Root == "F#" {
+			t.Errorf("%s is not spelled with flats", m.Name())
+		}
+	}
+}
+
+func TestFindScalesInvalid(t *testing.T) {
+	if _, err := FindScales(nil); err == nil {
+		t.Error("expected an error without notes")
+	}
+	if _, err := FindScales([]string{"C", "H"}); err == nil {
+		t.Error("expected an error from an invalid note")
+	}
+}
@@ -0,0 +1,167 @@
+package theory
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ChordMatch is a chord that contains the identified notes
+type ChordMatch struct {
+	Root  string
+	Chord string
+
+	// Lowest note if it isn't the root, i.e. the chord is an inversion or a
+	// slash chord
+	Bass string
+
+	// Notes of the chord that were not given
+	Missing []string
+
+	// The matches with a lower Score fit the notes better
+	Score int
+}
+
+// Name returns the chord name, e.g. "C Major" or "C Major/E"
+func (m ChordMatch) Name() string {
+	ret := m.Root + " " + m.Chord
+	if m.Bass != "" {
+		ret += "/" + m.Bass
+	}
+	return ret
+}
+
+func (m ChordMatch) String() string {
+	return m.Name()
+}
+
+// IdentifyChord returns the chords that consist of the given notes, best
+// match first. The notes are given from the lowest sounding note. The
+// chords may be missing their perfect fifth. The notes above the bass are
+// also matched as complete slash chords with the bass outside of the chord,
+// e.g. C/D, which are scored lower than the chords with all the notes.
+func IdentifyChord(notes []string) ([]ChordMatch, error) {
+	if len(notes) == 0 {
+		return nil, fmt.Errorf("no notes given")
+	}
+
+	played := map[int]string{}
+	for i := range notes {
+		pos, err := NotePosition(notes[i])
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := played[pos]; !ok {
+			played[pos], _ = NormalizeNote(notes[i])
+		}
+	}
+	bass, _ := NotePosition(notes[0])
+
+	ret := matchChords(played, bass)
+
+	// A triad or a larger chord above a bass outside of the chord
+	upper := map[int]string{}
+	for pos, name := range played {
+		if pos != bass {
+			upper[pos] = name
+		}
+	}
+	if len(upper) > 2 {
+		for _, m := range matchChords(upper, -1) {
+			if len(m.Missing) > 0 || chordHasNote(m.Root, m.Chord, played[bass]) {
+				continue
+			}
+			m.Bass = played[bass]
+			m.Score += 4
+			ret = append(ret, m)
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Score != ret[j].Score {
+			return ret[i].Score < ret[j].Score
+		}
+		return ret[i].Name() < ret[j].Name()
+	})
+
+	return ret, nil
+}
+
+func chordHasNote(root, chord, note string) bool {
+	notes, err := GetChord(root, chord)
+	if err != nil {
+		return false
+	}
+	for i := range notes {
+		if SameNote(notes[i], note) {
+			return true
+		}
+	}
+	return false
+}
+
+// Get the chords that have all the played notes. A bass of -1 matches any
+// root.
+func matchChords(played map[int]string, bass int) []ChordMatch {
+	var ret []ChordMatch
+	for root, rootName := range played {
+		for name, distances := range Chords {
+			b := bass
+			if b < 0 {
+				b = root
+			}
+			m, ok := matchChord(played, root, rootName, b, name, distances)
+			if ok {
+				ret = append(ret, m)
+			}
+		}
+	}
+	return ret
+}
+
+func matchChord(played map[int]string, root int, rootName string, bass int,
+	chord string, distances []int) (ChordMatch, bool) {
+
+	chordNotes, err := GetChord(rootName, chord)
+	if err != nil {
+		return ChordMatch{}, false
+	}
+
+	tones := map[int]string{}
+	for i, d := range distances {
+		tones[mod12(root+d)] = chordNotes[i]
+	}
+
+	for pos := range played {
+		if _, ok := tones[pos]; !ok {
+			return ChordMatch{}, false
+		}
+	}
+
+	ret := ChordMatch{
+		Root:  chordNotes[0],
+		Chord: chord,
+	}
+
+	for i, d := range distances {
+		pos := mod12(root + d)
+		if _, ok := played[pos]; ok {
+			continue
+		}
+		// Only the fifth may be left out
+		if d != 7 {
+			return ChordMatch{}, false
+		}
+		ret.Missing = append(ret.Missing, chordNotes[i])
+		ret.Score += 3
+	}
+
+	if bass != root {
+		ret.Bass = tones[bass]
+		ret.Score += 2
+	}
+
+	// Prefer the simpler chords
+	ret.Score += len(distances) - len(played)
+
+	return ret, true
+}
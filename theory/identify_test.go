@@ -0,0 +1,112 @@
+package theory
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIdentifyChord(t *testing.T) {
+	tests := []struct {
+		notes []string
+		first string
+
+		// Other matches in the order they are listed
+		others []string
+	}{
+		{[]string{"C", "E", "G"}, "C Major", nil},
+
+		// x32010 on EADGBE
+		{[]string{"C", "E", "G", "C", "E"}, "C Major", nil},
+		{[]string{"E", "C", "G"}, "C Major/E", nil},
+		{[]string{"F#", "D", "F#", "A", "D"}, "D Major/F#", nil},
+		{[]string{"G", "B", "D", "F"}, "G 7", []string{"B Diminished/G"}},
+		{[]string{"A", "C", "E", "G"}, "A m7", []string{"C 6/A", "C Major/A"}},
+
+		// The bass outside of the chord is scored below the full chord
+		{[]string{"D", "C", "E", "G"}, "C add9/D", []string{"C Major/D"}},
+
+		// The perfect fifth may be missing
+		{[]string{"C", "E", "Bb"}, "C 7", nil},
+	}
+	for _, tt := range tests {
+		matches, err := IdentifyChord(tt.notes)
+		if err != nil {
+			t.Errorf("IdentifyChord(%v): %v", tt.notes, err)
+			continue
+		}
+		if len(matches) == 0 || matches[0].Name() != tt.first {
+			t.Errorf("IdentifyChord(%v) = %v, want %s first", tt.notes, matches, tt.first)
+			continue
+		}
+
+		var got []string
+		for _, m := range matches[1:] {
+			for _, o := range tt.others {
+				if m.Name() == o {
+					got = append(got, o)
+				}
+			}
+		}
+		if len(tt.others) > 0 && !reflect.DeepEqual(got, tt.others) {
+			t.Errorf("IdentifyChord(%v) = %v, want %v in this order after %s",
+				tt.notes, matches, tt.others, tt.first)
+		}
+		for i := 1; i < len(matches); i++ {
+			if matches[i-1].Score > matches[i].Score {
+				t.Errorf("IdentifyChord(%v): %s is scored worse than the next %s",
+					tt.notes, matches[i-1], matches[i])
+			}
+		}
+	}
+}
+
+func TestIdentifyChordMissing(t *testing.T) {
+	matches, err := IdentifyChord([]string{"C", "E"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) == 0 || matches[0].Name() != "C Major" {
+		t.Fatalf("IdentifyChord(C E) = %v, want C Major first", matches)
+	}
+	if !reflect.DeepEqual(matches[0].Missing, []string{"G"}) {
+		t.Errorf("C Major is missing %v, want G", matches[0].Missing)
+	}
+}
+
+func TestIdentifyChordSlash(t *testing.T) {
+	matches, err := IdentifyChord([]string{"D", "C", "E", "G"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range matches {
+		if m.Bass == "" {
+			continue
+		}
+		if m.Bass != "D" {
+			t.Errorf("%s has the bass %s, want D", m, m.Bass)
+		}
+		if len(m.Missing) > 0 {
+			t.Errorf("the slash chord %s is missing %v", m, m.Missing)
+		}
+	}
+
+	// No slash chords are made of two notes above the bass
+	matches, err = IdentifyChord([]string{"D", "C", "E"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range matches {
+		if m.Bass != "" && !chordHasNote(m.Root, m.Chord, m.Bass) {
+			t.Errorf("unexpected slash chord %s", m)
+		}
+	}
+}
+
+func TestIdentifyChordInvalid(t *testing.T) {
+	if _, err := IdentifyChord(nil); err == nil {
+		t.Error("expected an error without notes")
+	}
+	if _, err := IdentifyChord([]string{"C", "H"}); err == nil {
+		t.Error("expected an error from an invalid note")
+	}
+}
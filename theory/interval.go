@@ -0,0 +1,100 @@
+package theory
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Semitones from the root to the major or perfect interval of each degree
+var degreeSemitones = []int{0, 2, 4, 5, 7, 9, 11}
+
+// intervalName names the interval from the root to the note by their
+// letters, e.g. "b3" or "#4". Seconds, fourths and sixths above an octave
+// are named as ninths, elevenths and thirteenths if compound is true.
+func intervalName(root, note spelling, compound bool) string {
+	steps := (note.letter - root.letter + len(letters)) % len(letters)
+	diff := mod12(note.position()-root.position()) - degreeSemitones[steps]
+	if diff > 6 {
+		diff -= 12
+	} else if diff < -6 {
+		diff += 12
+	}
+
+	degree := steps + 1
+	if compound && (degree == 2 || degree == 4 || degree == 6) {
+		degree += 7
+	}
+
+	acc := ""
+	if diff > 0 {
+		acc = strings.Repeat("#", diff)
+	} else if diff < 0 {
+		acc = strings.Repeat("b", -diff)
+	}
+	if degree == 1 && acc == "" {
+		return "R"
+	}
+	return acc + strconv.Itoa(degree)
+}
+
+// Interval returns the name of the interval from the root to the note,
+// e.g. "R", "b3", "5" or "#4". The name depends on the spelling of the
+// notes.
+func Interval(root, note string) (string, error) {
+	r, err := parseNote(root)
+	if err != nil {
+		return "", err
+	}
+	n, err := parseNote(note)
+	if err != nil {
+		return "", err
+	}
+	return intervalName(r, n, false), nil
+}
+
+// ScaleIntervals returns the intervals of the notes of the scale from its
+// root
+func ScaleIntervals(root, scale string) ([]string, error) {
+	notes, err := GetScale(root, scale)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]string, len(notes))
+	for i := range notes {
+		ret[i], _ = Interval(notes[0], notes[i])
+	}
+	return ret, nil
+}
+
+// ChordIntervals returns the intervals of the notes of the chord from its
+// root. The intervals above an octave are named as ninths, elevenths and
+// thirteenths.
+func ChordIntervals(root, chord string) ([]string, error) {
+	notes, err := GetChord(root, chord)
+	if err != nil {
+		return nil, err
+	}
+
+	name, _ := ChordName(chord)
+	r, _ := parseNote(notes[0])
+	ret := make([]string, len(notes))
+	for i, d := range chordDistances(name) {
+		n, _ := parseNote(notes[i])
+		ret[i] = intervalName(r, n, d >= 12)
+	}
+	return ret, nil
+}
+
+// IntervalDegree returns the degree of the interval without accidentals,
+// e.g. 3 for "b3" and 1 for "R". Returns 0 for an unknown interval.
+func IntervalDegree(interval string) int {
+	if interval == "R" {
+		return 1
+	}
+	ret, err := strconv.Atoi(strings.TrimLeft(interval, "#b"))
+	if err != nil {
+		return 0
+	}
+	return ret
+}
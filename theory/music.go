@@ -0,0 +1,303 @@
+// Package theory contains the notes, scales and chords known by fretnoter
+// and the functions to combine them.
+package theory
+
+import (
+	"fmt"
+	"sort"
+)
+
+// https://gist.github.com/inky/3188870
+
+// Notes has the pitch classes spelled with sharps
+var Notes = []string{"A", "A#", "B", "C", "C#", "D", "D#", "E", "F", "F#", "G", "G#"}
+
+// Scales as steps from the previous note
+var Scales = map[string][]int{
+	"Major (Ionian)":           {2, 2, 1, 2, 2, 2, 1},
+	"Dorian Mode":              {2, 1, 2, 2, 2, 1, 2},
+	"Phrygian Mode":            {1, 2, 2, 2, 1, 2, 2},
+	"Lydian Mode":              {2, 2, 2, 1, 2, 2, 1},
+	"Mixolydian Mode":          {2, 2, 1, 2, 2, 1, 2},
+	"Natural Minor (Aeolian)":  {2, 1, 2, 2, 1, 2, 2},
+	"Locrian Mode":             {1, 2, 2, 1, 2, 2, 2},
+	"Harmonic Minor":           {2, 1, 2, 2, 1, 3, 1},
+	"Locrian nat6":             {1, 2, 2, 1, 3, 1, 2},
+	"Ionian #5":                {2, 2, 1, 3, 1, 2, 1},
+	"Ukranian minor":           {2, 1, 3, 1, 2, 1, 2},
+	"Phrygian dominant":        {1, 3, 1, 2, 1, 2, 2},
+	"Lydian #2":                {3, 1, 2, 1, 2, 2, 1},
+	"Super Locrian diminished": {1, 2, 1, 2, 2, 1, 3},
+	"Diminished":               {2, 1, 2, 1, 2, 1, 2, 1},
+	"Dominant Diminished":      {1, 2, 1, 2, 1, 2, 1, 2},
+	"Pentatonic Major":         {2, 2, 3, 2, 3},
+	"Pentatonic Minor":         {3, 2, 2, 3, 2},
+	"Metallica":                {1, 1, 1, 2, 1, 1, 1, 2, 2},
+}
+
+// Chords as the distance from the root note
+var Chords = map[string][]int{
+	"Major":      {0, 4, 7},
+	"Minor":      {0, 3, 7},
+	"Augmented":  {0, 4, 8},
+	"Diminished": {0, 3, 6},
+	"sus2":       {0, 2, 7},
+	"sus4":       {0, 5, 7},
+	"Power":      {0, 7},
+	"6":          {0, 4, 7, 9},
+	"m6":         {0, 3, 7, 9},
+	"add9":       {0, 4, 7, 14},
+	"madd9":      {0, 3, 7, 14},
+	"sus4add9":   {0, 5, 7, 14},
+	"6/9":        {0, 4, 7, 9, 14},
+	"7":          {0, 4, 7, 10},
+	"m7":         {0, 3, 7, 10},
+	"maj7":       {0, 4, 7, 11},
+	"dim7":       {0, 3, 6, 9},
+	"dom7f5":     {0, 4, 6, 10},
+	"halfdim7":   {0, 3, 6, 10},
+	"majdim7":    {0, 3, 6, 11},
+	"minmaj7":    {0, 3, 7, 11},
+	"augmaj7":    {0, 4, 8, 11},
+	"aug7":       {0, 4, 8, 10},
+	"7sus2":      {0, 2, 7, 10},
+	"7sus4":      {0, 5, 7, 10},
+	"7b9":        {0, 4, 7, 10, 13},
+	"7#9":        {0, 4, 7, 10, 15},
+	"9":          {0, 4, 7, 10, 14},
+	"m9":         {0, 3, 7, 10, 14},
+	"maj9":       {0, 4, 7, 11, 14},
+	"9sus4":      {0, 5, 7, 10, 14},
+	"11":         {0, 4, 7, 10, 14, 17},
+	"m11":        {0, 3, 7, 10, 14, 17},
+	"13":         {0, 4, 7, 10, 14, 21},
+}
+
+// ChordAliases has other common names of the chords in Chords
+var ChordAliases = map[string]string{
+	"maj":     "Major",
+	"M":       "Major",
+	"m":       "Minor",
+	"min":     "Minor",
+	"aug":     "Augmented",
+	"+":       "Augmented",
+	"dim":     "Diminished",
+	"°":       "Diminished",
+	"5":       "Power",
+	"dom7":    "7",
+	"min7":    "m7",
+	"M7":      "maj7",
+	"°7":      "dim7",
+	"7b5":     "dom7f5",
+	"m7b5":    "halfdim7",
+	"ø7":      "halfdim7",
+	"mM7":     "minmaj7",
+	"m(maj7)": "minmaj7",
+	"maj7#5":  "augmaj7",
+	"+7":      "aug7",
+	"7#5":     "aug7",
+	"add2":    "add9",
+	"69":      "6/9",
+	"sus":     "sus4",
+	"7sus":    "7sus4",
+	"M9":      "maj9",
+}
+
+// Chords built from chord symbols, e.g. "m7b5add11". They are found by
+// ChordName but they are not listed with Chords.
+var symbolChords = map[string][]int{}
+
+// ChordName returns the name of the chord in Chords or of a chord built
+// from a chord symbol. The chord may be given with its name or one of its
+// ChordAliases.
+func ChordName(chord string) (string, bool) {
+	if _, ok := Chords[chord]; ok {
+		return chord, true
+	}
+	if name, ok := ChordAliases[chord]; ok {
+		if _, ok := Chords[name]; ok {
+			return name, true
+		}
+	}
+	if _, ok := symbolChords[chord]; ok {
+		return chord, true
+	}
+	return "", false
+}
+
+// Get the distances of the chord returned by ChordName
+func chordDistances(name string) []int {
+	if distances, ok := Chords[name]; ok {
+		return distances
+	}
+	return symbolChords[name]
+}
+
+// Aliases returns the aliases of the chord in alphabetical order
+func Aliases(chord string) []string {
+	var ret []string
+	for alias, name := range ChordAliases {
+		if name == chord {
+			ret = append(ret, alias)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// NotePosition returns the index of the note in Notes. The note may be
+// spelled with sharps, flats, double sharps or double flats.
+func NotePosition(note string) (int, error) {
+	s, err := parseNote(note)
+	if err != nil {
+		return -1, err
+	}
+	return s.position(), nil
+}
+
+// GetNote returns the note the given amount of semitones above note
+func GetNote(note string, steps int) (string, error) {
+	pos, err := NotePosition(note)
+	if err != nil {
+		return "", err
+	}
+
+	return Notes[mod12(pos+steps)], nil
+}
+
+// GetScale returns the notes of the scale starting from the root note.
+// Heptatonic scales use each letter once. Other scales are spelled
+// according to the key signature of the root. The root keeps its spelling
+// unless a note would need more than two accidentals.
+func GetScale(note, scale string) ([]string, error) {
+	root, err := parseNote(note)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := Scales[scale]; !ok {
+		return nil, &UnknownScaleError{Scale: scale}
+	}
+
+	distances := stepsToDistances(Scales[scale])
+
+	if len(distances) == len(letters) {
+		letterSteps := []int{0, 1, 2, 3, 4, 5, 6}
+		return spellingsToStrings(spellDegrees(root, distances, letterSteps)), nil
+	}
+
+	// Scales with a minor third and without a major third use the minor key
+	minor := false
+	for _, d := range distances {
+		if d == 4 {
+			minor = false
+			break
+		}
+		minor = minor || d == 3
+	}
+
+	return spellingsToStrings(spellWithKey(root, distances, minor)), nil
+}
+
+// GetChord returns the notes of the chord starting from the root note. The
+// notes are spelled by their interval from the root, e.g. the diminished
+// seventh of C is Bbb.
+func GetChord(note, chord string) ([]string, error) {
+	root, err := parseNote(note)
+	if err != nil {
+		return nil, err
+	}
+
+	name, ok := ChordName(chord)
+	if !ok {
+		return nil, &UnknownChordError{Chord: chord}
+	}
+
+	distances := chordDistances(name)
+	return spellingsToStrings(spellDegrees(root, distances, chordLetterSteps(distances))), nil
+}
+
+// GetSlashChord returns the notes of the chord starting from the bass note.
+// A bass note of the chord is an inversion, e.g. C/E is E G C, and other
+// bass notes are added below the chord, e.g. C/D is D C E G. The bass is
+// spelled as in the chord.
+func GetSlashChord(note, chord, bass string) ([]string, error) {
+	notes, err := GetChord(note, chord)
+	if err != nil {
+		return nil, err
+	}
+	b, err := NormalizeNote(bass)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range notes {
+		if SameNote(notes[i], b) {
+			return append(append([]string{}, notes[i:]...), notes[:i]...), nil
+		}
+	}
+	return append([]string{b}, notes...), nil
+}
+
+// Inversion returns the bass note of the inversion of the chord. The first
+// inversion has the second note of the chord in the bass, e.g. E in C
+// major, and the inversion 0 is the root position.
+func Inversion(note, chord string, inversion int) (string, error) {
+	notes, err := GetChord(note, chord)
+	if err != nil {
+		return "", err
+	}
+	if inversion < 0 || inversion >= len(notes) {
+		return "", fmt.Errorf("inversion must be between 0 and %d for %s %s",
+			len(notes)-1, notes[0], chord)
+	}
+	return notes[inversion], nil
+}
+
+// IsChordInScale tells if all chordNotes are in scaleNotes. Enharmonic
+// notes are considered the same.
+func IsChordInScale(chordNotes, scaleNotes []string) bool {
+	for i := range chordNotes {
+		found := false
+		for j := range scaleNotes {
+			if SameNote(chordNotes[i], scaleNotes[j]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ChordMap has chord names with the root note as the key
+type ChordMap map[string][]string
+
+// Get the chords that can be played with the notes in the given scale
+func GetChordsInScale(root, scale string) (ChordMap, error) {
+	scalenotes, err := GetScale(root, scale)
+	if err != nil {
+		return nil, err
+	}
+
+	scalechords := ChordMap{}
+
+	// Get the chords in a map with the Note name as spelled in the scale as
+	// the key
+	for _, note := range scalenotes {
+		for j := range Chords {
+			notes, err := GetChord(note, j)
+			if err != nil {
+				return nil, err
+			}
+			if IsChordInScale(notes, scalenotes) {
+				scalechords[note] = append(scalechords[note], j)
+			}
+		}
+		sort.Strings(scalechords[note])
+	}
+
+	return scalechords, err
+}
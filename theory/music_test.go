@@ -0,0 +1,88 @@
+package theory
+
+import (
+	"reflect"
+	"testing"
+)
+
+// The notes of the chords with C as the root
+var cChords = map[string][]string{
+	"Major":      {"C", "E", "G"},
+	"Minor":      {"C", "Eb", "G"},
+	"Augmented":  {"C", "E", "G#"},
+	"Diminished": {"C", "Eb", "Gb"},
+	"sus2":       {"C", "D", "G"},
+	"sus4":       {"C", "F", "G"},
+	"Power":      {"C", "G"},
+	"6":          {"C", "E", "G", "A"},
+	"m6":         {"C", "Eb", "G", "A"},
+	"add9":       {"C", "E", "G", "D"},
+	"madd9":      {"C", "Eb", "G", "D"},
+	"sus4add9":   {"C", "F", "G", "D"},
+	"6/9":        {"C", "E", "G", "A", "D"},
+	"7":          {"C", "E", "G", "Bb"},
+	"m7":         {"C", "Eb", "G", "Bb"},
+	"maj7":       {"C", "E", "G", "B"},
+	"dim7":       {"C", "Eb", "Gb", "Bbb"},
+	"dom7f5":     {"C", "E", "Gb", "Bb"},
+	"halfdim7":   {"C", "Eb", "Gb", "Bb"},
+	"majdim7":    {"C", "Eb", "Gb", "B"},
+	"minmaj7":    {"C", "Eb", "G", "B"},
+	"augmaj7":    {"C", "E", "G#", "B"},
+	"aug7":       {"C", "E", "G#", "Bb"},
+	"7sus2":      {"C", "D", "G", "Bb"},
+	"7sus4":      {"C", "F", "G", "Bb"},
+	"7b9":        {"C", "E", "G", "Bb", "Db"},
+	"7#9":        {"C", "E", "G", "Bb", "D#"},
+	"9":          {"C", "E", "G", "Bb", "D"},
+	"m9":         {"C", "Eb", "G", "Bb", "D"},
+	"maj9":       {"C", "E", "G", "B", "D"},
+	"9sus4":      {"C", "F", "G", "Bb", "D"},
+	"11":         {"C", "E", "G", "Bb", "D", "F"},
+	"m11":        {"C", "Eb", "G", "Bb", "D", "F"},
+	"13":         {"C", "E", "G", "Bb", "D", "A"},
+}
+
+func TestGetChord(t *testing.T) {
+	for name := range Chords {
+		if _, ok := cChords[name]; !ok {
+			t.Errorf("no reference notes for chord '%s'", name)
+		}
+	}
+
+	for name, want := range cChords {
+		got, err := GetChord("C", name)
+		if err != nil {
+			t.Errorf("GetChord(C, %s): %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GetChord(C, %s) = %v, want %v", name, got, want)
+		}
+	}
+
+	for alias, name := range ChordAliases {
+		want, ok := cChords[name]
+		if !ok {
+			t.Errorf("alias '%s' of an unknown chord '%s'", alias, name)
+			continue
+		}
+		got, err := GetChord("C", alias)
+		if err != nil {
+			t.Errorf("GetChord(C, %s): %v", alias, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GetChord(C, %s) = %v, want %v", alias, got, want)
+		}
+	}
+}
+
+func TestGetChordUnknown(t *testing.T) {
+	if _, err := GetChord("C", "Nonexistent"); err == nil {
+		t.Error("expected an error from an unknown chord")
+	}
+	if _, err := GetChord("H", "Major"); err == nil {
+		t.Error("expected an error from an invalid root")
+	}
+}
@@ -0,0 +1,101 @@
+package theory
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Pitch is a note in a given octave as a MIDI note number. The octaves are
+// in the scientific pitch notation where the middle C, C4, is 60 and the
+// low E string of a guitar is E2.
+type Pitch int
+
+// Semitones from C to each letter
+var letterSemitones = []int{9, 11, 0, 2, 4, 5, 7}
+
+// splitOctave splits the octave number from the end of a note name. Returns
+// false if the note has no octave.
+func splitOctave(note string) (string, int, bool) {
+	i := strings.IndexAny(note, "-0123456789")
+	if i <= 0 {
+		return note, 0, false
+	}
+	octave, err := strconv.Atoi(note[i:])
+	if err != nil {
+		return note, 0, false
+	}
+	return note[:i], octave, true
+}
+
+// NewPitch returns the pitch of the note in the given octave. The octave
+// follows the letter of the note, e.g. B#3 is the same pitch as C4.
+func NewPitch(note string, octave int) (Pitch, error) {
+	s, err := parseNote(note)
+	if err != nil {
+		return 0, err
+	}
+	return Pitch(12*(octave+1) + letterSemitones[s.letter] + s.accidental), nil
+}
+
+// ParsePitch parses a note with an octave, e.g. "E2" or "Bb3"
+func ParsePitch(pitch string) (Pitch, error) {
+	note, octave, ok := splitOctave(pitch)
+	if !ok {
+		return 0, &UnknownNoteError{Note: pitch}
+	}
+	return NewPitch(note, octave)
+}
+
+// NormalizePitch returns the note with an uppercase letter and ASCII
+// accidentals. The octave is kept if it is given.
+func NormalizePitch(pitch string) (string, error) {
+	note, octave, ok := splitOctave(pitch)
+	ret, err := NormalizeNote(note)
+	if err != nil || !ok {
+		return ret, err
+	}
+	return ret + strconv.Itoa(octave), nil
+}
+
+// Position returns the index of the pitch class in Notes
+func (p Pitch) Position() int {
+	return mod12(int(p) - 33)
+}
+
+// Octave returns the octave of the pitch when spelled with Notes
+func (p Pitch) Octave() int {
+	return int(p)/12 - 1
+}
+
+// Spell returns the pitch spelled as the given note of the same pitch
+// class, e.g. Pitch 60 spelled as "B#" is "B#3"
+func (p Pitch) Spell(note string) (string, error) {
+	s, err := parseNote(note)
+	if err != nil {
+		return "", err
+	}
+	if s.position() != p.Position() {
+		return "", &UnknownNoteError{Note: note}
+	}
+	octave := (int(p)-letterSemitones[s.letter]-s.accidental)/12 - 1
+	return s.String() + strconv.Itoa(octave), nil
+}
+
+func (p Pitch) String() string {
+	return Notes[p.Position()] + strconv.Itoa(p.Octave())
+}
+
+// GetChordPitches returns the pitches of the chord above the root. The
+// intervals above an octave, such as ninths, are kept.
+func GetChordPitches(root Pitch, chord string) ([]Pitch, error) {
+	name, ok := ChordName(chord)
+	if !ok {
+		return nil, &UnknownChordError{Chord: chord}
+	}
+	distances := chordDistances(name)
+	ret := make([]Pitch, len(distances))
+	for i := range distances {
+		ret[i] = root + Pitch(distances[i])
+	}
+	return ret, nil
+}
@@ -0,0 +1,246 @@
+package theory
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Roman numerals of the scale degrees from I to VII
+var numerals = []string{"I", "II", "III", "IV", "V", "VI", "VII"}
+
+// The scale the accidentals of the Roman numerals are relative to
+const majorScale = "Major (Ionian)"
+
+// ProgressionChord is a chord of a progression given as a Roman numeral
+type ProgressionChord struct {
+	Numeral string
+	Root    string
+	Chord   string
+}
+
+// Name returns the chord name, e.g. "C Major"
+func (c ProgressionChord) Name() string {
+	return c.Root + " " + c.Chord
+}
+
+func (c ProgressionChord) String() string {
+	return c.Numeral + ": " + c.Name()
+}
+
+// Chords of the qualities of the Roman numerals by the extension after the
+// numeral
+var numeralChords = map[string]map[string]string{
+	"major": {
+		"":     "Major",
+		"maj7": "maj7",
+	},
+	"minor": {
+		"":     "Minor",
+		"maj7": "minmaj7",
+	},
+	"dim": {
+		"":  "Diminished",
+		"7": "dim7",
+	},
+	"halfdim": {
+		"":  "halfdim7",
+		"7": "halfdim7",
+	},
+	"aug": {
+		"":     "Augmented",
+		"7":    "aug7",
+		"maj7": "augmaj7",
+	},
+}
+
+// Get the chord of the quality and the extension. Major and minor chords
+// may have any known extension, e.g. "V7" is a 7 chord and "ii9" a m9
+// chord.
+func numeralChord(quality, ext string) (string, bool) {
+	if chord, ok := numeralChords[quality][ext]; ok {
+		return chord, true
+	}
+	var chord string
+	switch quality {
+	case "major":
+		chord = ext
+	case "minor":
+		chord = "m" + ext
+	default:
+		return "", false
+	}
+	if name, ok := ChordName(chord); ok {
+		return name, true
+	}
+
+	// Suspended chords have no third, e.g. "Vsus4"
+	if name, ok := ChordName(ext); ok && strings.Contains(ext, "sus") {
+		return name, true
+	}
+	return "", false
+}
+
+// Split the accidental in front of the numeral
+func numeralAccidental(numeral string) (int, string) {
+	for _, a := range []struct {
+		token string
+		acc   int
+	}{{"b", -1}, {"♭", -1}, {"#", 1}, {"♯", 1}} {
+		if strings.HasPrefix(numeral, a.token) {
+			return a.acc, numeral[len(a.token):]
+		}
+	}
+	return 0, numeral
+}
+
+// Split the longest Roman numeral from the start. Returns the degree from 1
+// and if the numeral is in uppercase.
+func splitNumeral(numeral string) (int, bool, string) {
+	for d := len(numerals); d > 0; d-- {
+		n := numerals[d-1]
+		if strings.HasPrefix(numeral, n) {
+			return d, true, numeral[len(n):]
+		}
+		if strings.HasPrefix(numeral, strings.ToLower(n)) {
+			return d, false, numeral[len(n):]
+		}
+	}
+	return 0, false, numeral
+}
+
+// ParseNumeral returns the chord of the Roman numeral in the scale. The
+// degree may be raised or lowered with an accidental relative to the major
+// scale, e.g. "bVII" is Bb in both C major and C minor. Uppercase
+// numerals are major and lowercase minor chords. The quality may be
+// changed with "°" or "o" for diminished, "ø" for half-diminished and "+"
+// for augmented chords. An extension such as "7", "maj7" or "sus4" may
+// follow, e.g. "V7" or "ii7".
+func ParseNumeral(numeral, root, scale string) (ProgressionChord, error) {
+	notes, err := GetScale(root, scale)
+	if err != nil {
+		return ProgressionChord{}, err
+	}
+	if len(notes) != len(numerals) {
+		return ProgressionChord{}, fmt.Errorf("roman numerals need a scale of %d notes, '%s' has %d",
+			len(numerals), scale, len(notes))
+	}
+
+	acc, rest := numeralAccidental(numeral)
+	if a, _ := numeralAccidental(rest); a != 0 {
+		return ProgressionChord{}, &InvalidNumeralError{
+			Numeral: numeral,
+			Reason:  "only one accidental is allowed",
+		}
+	}
+	degree, upper, rest := splitNumeral(rest)
+	if degree == 0 {
+		return ProgressionChord{}, &InvalidNumeralError{
+			Numeral: numeral,
+			Reason:  "it must have a numeral from I to VII",
+		}
+	}
+	if acc != 0 {
+		notes, err = GetScale(root, majorScale)
+		if err != nil {
+			return ProgressionChord{}, err
+		}
+	}
+
+	quality := "minor"
+	if upper {
+		quality = "major"
+	}
+	switch {
+	case strings.HasPrefix(rest, "°"):
+		quality, rest = "dim", rest[len("°"):]
+	case strings.HasPrefix(rest, "o"):
+		quality, rest = "dim", rest[1:]
+	case strings.HasPrefix(rest, "ø"):
+		quality, rest = "halfdim", rest[len("ø"):]
+	case strings.HasPrefix(rest, "+"):
+		quality, rest = "aug", rest[1:]
+	}
+
+	chord, ok := numeralChord(quality, rest)
+	if !ok {
+		return ProgressionChord{}, &InvalidNumeralError{
+			Numeral: numeral,
+			Reason:  fmt.Sprintf("unknown chord '%s'", rest),
+		}
+	}
+
+	s, err := parseNote(notes[degree-1])
+	if err != nil {
+		return ProgressionChord{}, err
+	}
+	s.accidental += acc
+	if s.accidental < -2 || s.accidental > 2 {
+		// E.g. the raised seventh of G# major is G# instead of F###
+		s = spellPreferring(s.position(), acc < 0)
+	}
+
+	return ProgressionChord{
+		Numeral: numeral,
+		Root:    s.String(),
+		Chord:   chord,
+	}, nil
+}
+
+// ParseProgression returns the chords of the Roman numerals in the scale.
+// The numerals are separated by spaces, dashes or commas, e.g. "I-vi-IV-V".
+func ParseProgression(progression, root, scale string) ([]ProgressionChord, error) {
+	fields := strings.FieldsFunc(progression, func(r rune) bool {
+		return r == ' ' || r == '-' || r == ',' || r == '–' || r == '|'
+	})
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no roman numerals given")
+	}
+
+	ret := make([]ProgressionChord, 0, len(fields))
+	for _, numeral := range fields {
+		c, err := ParseNumeral(numeral, root, scale)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, c)
+	}
+	return ret, nil
+}
+
+// ScaleNumerals returns the Roman numerals of the triads on each degree of
+// the scale, e.g. I ii iii IV V vi vii° for the major scale.
+func ScaleNumerals(root, scale string) ([]string, error) {
+	notes, err := GetScale(root, scale)
+	if err != nil {
+		return nil, err
+	}
+	if len(notes) != len(numerals) {
+		return nil, fmt.Errorf("roman numerals need a scale of %d notes, '%s' has %d",
+			len(numerals), scale, len(notes))
+	}
+
+	pos := make([]int, len(notes))
+	for i := range notes {
+		pos[i], err = NotePosition(notes[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ret := make([]string, len(notes))
+	for i := range notes {
+		third := mod12(pos[(i+2)%len(pos)] - pos[i])
+		fifth := mod12(pos[(i+4)%len(pos)] - pos[i])
+		switch {
+		case third == 3 && fifth == 6:
+			ret[i] = strings.ToLower(numerals[i]) + "°"
+		case third == 3:
+			ret[i] = strings.ToLower(numerals[i])
+		case third == 4 && fifth == 8:
+			ret[i] = numerals[i] + "+"
+		default:
+			ret[i] = numerals[i]
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,68 @@
+package theory
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseNumeral(t *testing.T) {
+	tests := []struct {
+		numeral string
+		root    string
+		scale   string
+		want    string
+	}{
+		{"I", "C", "Major (Ionian)", "C Major"},
+		{"ii", "C", "Major (Ionian)", "D Minor"},
+		{"V7", "C", "Major (Ionian)", "G 7"},
+		{"ii7", "C", "Major (Ionian)", "D m7"},
+		{"IVmaj7", "C", "Major (Ionian)", "F maj7"},
+		{"Vsus4", "C", "Major (Ionian)", "G sus4"},
+		{"i", "A", "Natural Minor (Aeolian)", "A Minor"},
+		{"III", "A", "Natural Minor (Aeolian)", "C Major"},
+
+		// The accidentals are relative to the major scale
+		{"bVII", "C", "Major (Ionian)", "Bb Major"},
+		{"bVII", "C", "Natural Minor (Aeolian)", "Bb Major"},
+		{"♭III", "C", "Major (Ionian)", "Eb Major"},
+		{"#iv°", "C", "Major (Ionian)", "F# Diminished"},
+		{"♯IV", "C", "Major (Ionian)", "F# Major"},
+		{"bII", "Db", "Major (Ionian)", "Ebb Major"},
+		{"#VII", "G#", "Major (Ionian)", "G# Major"},
+
+		{"vii°", "C", "Major (Ionian)", "B Diminished"},
+		{"viio", "C", "Major (Ionian)", "B Diminished"},
+		{"vii°7", "C", "Major (Ionian)", "B dim7"},
+		{"viiø", "C", "Major (Ionian)", "B halfdim7"},
+		{"viiø7", "C", "Major (Ionian)", "B halfdim7"},
+		{"III+", "A", "Harmonic Minor", "C Augmented"},
+		{"III+7", "A", "Harmonic Minor", "C aug7"},
+	}
+	for _, tt := range tests {
+		c, err := ParseNumeral(tt.numeral, tt.root, tt.scale)
+		if err != nil {
+			t.Errorf("ParseNumeral(%s, %s %s): %v", tt.numeral, tt.root, tt.scale, err)
+			continue
+		}
+		if c.Name() != tt.want {
+			t.Errorf("ParseNumeral(%s, %s %s) = %s, want %s",
+				tt.numeral, tt.root, tt.scale, c.Name(), tt.want)
+		}
+	}
+}
+
+func TestParseNumeralInvalid(t *testing.T) {
+	tests := []string{"", "bbII", "#bIV", "X", "b", "Vfoo", "ii°maj7"}
+	for _, numeral := range tests {
+		_, err := ParseNumeral(numeral, "Db", "Major (Ionian)")
+		var ne *InvalidNumeralError
+		if !errors.As(err, &ne) {
+			t.Errorf("ParseNumeral(%s) = %v, want an InvalidNumeralError", numeral, err)
+		}
+	}
+
+	_, err := ParseNumeral("I", "C", "Pentatonic Major")
+	if err == nil {
+		t.Error("expected an error from a pentatonic scale")
+	}
+}
@@ -0,0 +1,302 @@
+package theory
+
+import (
+	"strings"
+)
+
+// The natural note letters and their positions in Notes
+const letters = "ABCDEFG"
+
+var letterPositions = []int{0, 2, 3, 5, 7, 8, 10}
+
+// RootNotes has the common spellings of every note for selecting a key
+var RootNotes = []string{
+	"A", "A#", "Bb", "B", "C", "C#", "Db", "D", "D#", "Eb", "E",
+	"F", "F#", "Gb", "G", "G#", "Ab",
+}
+
+type spelling struct {
+	letter     int
+	accidental int
+}
+
+func (s spelling) String() string {
+	acc := ""
+	if s.accidental > 0 {
+		acc = strings.Repeat("#", s.accidental)
+	} else if s.accidental < 0 {
+		acc = strings.Repeat("b", -s.accidental)
+	}
+	return letters[s.letter:s.letter+1] + acc
+}
+
+func (s spelling) position() int {
+	return mod12(letterPositions[s.letter] + s.accidental)
+}
+
+func mod12(pos int) int {
+	return ((pos % 12) + 12) % 12
+}
+
+// parseNote parses a note name such as "Bb", "F#", "Cx" or "Ebb". The
+// letter may be given in lowercase. An octave after the note is ignored.
+func parseNote(note string) (spelling, error) {
+	var ret spelling
+	orig := note
+	note, _, _ = splitOctave(note)
+	if note == "" {
+		return ret, &UnknownNoteError{Note: orig}
+	}
+
+	ret.letter = strings.IndexByte(letters, strings.ToUpper(note[:1])[0])
+	if ret.letter < 0 {
+		return ret, &UnknownNoteError{Note: orig}
+	}
+
+	acc := strings.NewReplacer("♯", "#", "♭", "b", "x", "##", "𝄪", "##", "𝄫", "bb").
+		Replace(note[1:])
+	switch acc {
+	case "":
+	case "#":
+		ret.accidental = 1
+	case "##":
+		ret.accidental = 2
+	case "b":
+		ret.accidental = -1
+	case "bb":
+		ret.accidental = -2
+	default:
+		return ret, &UnknownNoteError{Note: orig}
+	}
+
+	return ret, nil
+}
+
+// spellAs spells the note at the given position in Notes with the given
+// letter. Returns false if it would need more than two accidentals.
+func spellAs(pos, letter int) (spelling, bool) {
+	acc := mod12(pos - letterPositions[letter])
+	if acc > 6 {
+		acc -= 12
+	}
+	return spelling{letter, acc}, acc >= -2 && acc <= 2
+}
+
+// NormalizeNote returns the note name with an uppercase letter and ASCII
+// accidentals
+func NormalizeNote(note string) (string, error) {
+	s, err := parseNote(note)
+	if err != nil {
+		return "", err
+	}
+	return s.String(), nil
+}
+
+// TransposeNote returns the note the given amount of semitones higher.
+// The note is spelled as a natural if possible and otherwise with flats if
+// the original note has a flat and with sharps if not.
+func TransposeNote(note string, steps int) (string, error) {
+	s, err := parseNote(note)
+	if err != nil {
+		return "", err
+	}
+	return spellPreferring(mod12(s.position()+steps), s.accidental < 0).String(), nil
+}
+
+// NoteDistance returns the semitones from a note to the nearest other note
+// above or below it, from -5 to 6.
+func NoteDistance(from, to string) (int, error) {
+	f, err := parseNote(from)
+	if err != nil {
+		return 0, err
+	}
+	t, err := parseNote(to)
+	if err != nil {
+		return 0, err
+	}
+	ret := mod12(t.position() - f.position())
+	if ret > 6 {
+		ret -= 12
+	}
+	return ret, nil
+}
+
+// SameNote tells if the two note names are the same pitch class, e.g. "A#"
+// and "Bb"
+func SameNote(a, b string) bool {
+	pa, err := NotePosition(a)
+	if err != nil {
+		return false
+	}
+	pb, err := NotePosition(b)
+	if err != nil {
+		return false
+	}
+	return pa == pb
+}
+
+// rootCandidates returns the given spelling of the root and, if it has
+// accidentals, its enharmonic spellings with at most one accidental
+func rootCandidates(root spelling) []spelling {
+	ret := []spelling{root}
+	if root.accidental == 0 {
+		return ret
+	}
+	for l := range letterPositions {
+		s, ok := spellAs(root.position(), l)
+		if ok && s != root && s.accidental >= -1 && s.accidental <= 1 {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+func accidentalCount(notes []spelling) int {
+	count := 0
+	for i := range notes {
+		if notes[i].accidental < 0 {
+			count -= notes[i].accidental
+		} else {
+			count += notes[i].accidental
+		}
+	}
+	return count
+}
+
+// spellDegrees spells notes that are the given distances from the root.
+// Each note uses the letter that is the given amount of letters from the
+// root letter. The root keeps its spelling unless a note would need more
+// than two accidentals, in which case the root is respelled enharmonically
+// with the fewest accidentals.
+func spellDegrees(root spelling, distances []int, letterSteps []int) []spelling {
+	var best []spelling
+	bestCount := -1
+
+	for i, r := range rootCandidates(root) {
+		notes := make([]spelling, len(distances))
+		count := 0
+		for i := range distances {
+			s, ok := spellAs(r.position()+distances[i], (r.letter+letterSteps[i])%len(letters))
+			if !ok {
+				// Make unspellable candidates lose
+				s = spellPreferring(mod12(r.position()+distances[i]), r.accidental < 0)
+				count += 100
+			}
+			notes[i] = s
+		}
+		if i == 0 && count < 100 {
+			return notes
+		}
+		count += accidentalCount(notes)
+		if bestCount < 0 || count < bestCount {
+			best = notes
+			bestCount = count
+		}
+	}
+
+	return best
+}
+
+// spellWithKey spells notes that are the given distances from the root
+// according to the key signature of the major or the natural minor scale of
+// the root. Notes not in the key use the accidental of the key.
+func spellWithKey(root spelling, distances []int, minor bool) []spelling {
+	parent := Scales["Major (Ionian)"]
+	if minor {
+		parent = Scales["Natural Minor (Aeolian)"]
+	}
+
+	key := spellDegrees(root, stepsToDistances(parent), []int{0, 1, 2, 3, 4, 5, 6})
+	flats := false
+	for i := range key {
+		if key[i].accidental < 0 {
+			flats = true
+		}
+	}
+
+	ret := make([]spelling, len(distances))
+	for i := range distances {
+		pos := mod12(key[0].position() + distances[i])
+		found := false
+		for j := range key {
+			if key[j].position() == pos {
+				ret[i] = key[j]
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		ret[i] = spellPreferring(pos, flats)
+	}
+	return ret
+}
+
+// spellPreferring spells the note as a natural if possible and otherwise
+// with a flat or a sharp
+func spellPreferring(pos int, flats bool) spelling {
+	want := []int{0, 1}
+	if flats {
+		want = []int{0, -1}
+	}
+	for _, acc := range want {
+		for l := range letterPositions {
+			s, _ := spellAs(pos, l)
+			if s.accidental == acc {
+				return s
+			}
+		}
+	}
+	return spelling{}
+}
+
+func stepsToDistances(steps []int) []int {
+	ret := make([]int, len(steps))
+	for i := 1; i < len(steps); i++ {
+		ret[i] = ret[i-1] + steps[i-1]
+	}
+	return ret
+}
+
+// Amount of letters from the root for chord intervals
+var intervalLetters = []int{0, 1, 1, 2, 2, 3, 4, 4, 4, 5, 6, 6}
+
+func chordLetterSteps(distances []int) []int {
+	has := func(d int) bool {
+		for i := range distances {
+			if distances[i] == d {
+				return true
+			}
+		}
+		return false
+	}
+
+	ret := make([]int, len(distances))
+	for i, d := range distances {
+		ret[i] = intervalLetters[d%12]
+		switch {
+		// Diminished seventh
+		case d == 9 && has(3) && has(6) && !has(10) && !has(11):
+			ret[i] = 6
+		// Augmented ninth
+		case d%12 == 3 && (d > 12 || has(4)):
+			ret[i] = 1
+		// Augmented eleventh
+		case d == 18:
+			ret[i] = 3
+		// Minor thirteenth
+		case d == 20:
+			ret[i] = 5
+		}
+	}
+	return ret
+}
+
+func spellingsToStrings(notes []spelling) []string {
+	ret := make([]string, len(notes))
+	for i := range notes {
+		ret[i] = notes[i].String()
+	}
+	return ret
+}
@@ -0,0 +1,153 @@
+package theory
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetScale(t *testing.T) {
+	tests := []struct {
+		root  string
+		scale string
+		want  []string
+	}{
+		{"C", "Major (Ionian)", []string{"C", "D", "E", "F", "G", "A", "B"}},
+		{"F", "Major (Ionian)", []string{"F", "G", "A", "Bb", "C", "D", "E"}},
+		{"Bb", "Major (Ionian)", []string{"Bb", "C", "D", "Eb", "F", "G", "A"}},
+		{"Gb", "Major (Ionian)", []string{"Gb", "Ab", "Bb", "Cb", "Db", "Eb", "F"}},
+		{"D#", "Major (Ionian)", []string{"D#", "E#", "F##", "G#", "A#", "B#", "C##"}},
+		{"C#", "Natural Minor (Aeolian)", []string{"C#", "D#", "E", "F#", "G#", "A", "B"}},
+		{"C#", "Harmonic Minor", []string{"C#", "D#", "E", "F#", "G#", "A", "B#"}},
+		{"Bb", "Locrian Mode", []string{"Bb", "Cb", "Db", "Eb", "Fb", "Gb", "Ab"}},
+		{"Bb", "Pentatonic Minor", []string{"Bb", "Db", "Eb", "F", "Ab"}},
+
+		// The root keeps its spelling while the notes need at most two
+		// accidentals
+		{"Fb", "Major (Ionian)", []string{"Fb", "Gb", "Ab", "Bbb", "Cb", "Db", "Eb"}},
+		{"Fb", "Locrian Mode", []string{"Fb", "Gbb", "Abb", "Bbb", "Cbb", "Dbb", "Ebb"}},
+
+		// Lowercase letters and other accidentals are accepted
+		{"bb", "Major (Ionian)", []string{"Bb", "C", "D", "Eb", "F", "G", "A"}},
+		{"B♭", "Major (Ionian)", []string{"Bb", "C", "D", "Eb", "F", "G", "A"}},
+	}
+	for _, tt := range tests {
+		got, err := GetScale(tt.root, tt.scale)
+		if err != nil {
+			t.Errorf("GetScale(%s, %s): %v", tt.root, tt.scale, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GetScale(%s, %s) = %v, want %v", tt.root, tt.scale, got, tt.want)
+		}
+	}
+
+	if _, err := GetScale("C", "Nonexistent"); err == nil {
+		t.Error("expected an error from an unknown scale")
+	}
+}
+
+func TestGetChordSpelling(t *testing.T) {
+	tests := []struct {
+		root  string
+		chord string
+		want  []string
+	}{
+		{"Eb", "Diminished", []string{"Eb", "Gb", "Bbb"}},
+		{"Bb", "7", []string{"Bb", "D", "F", "Ab"}},
+		{"D#", "Major", []string{"D#", "F##", "A#"}},
+
+		// The root is respelled when a note would need three accidentals
+		{"Fb", "dim7", []string{"E", "G", "Bb", "Db"}},
+	}
+	for _, tt := range tests {
+		got, err := GetChord(tt.root, tt.chord)
+		if err != nil {
+			t.Errorf("GetChord(%s, %s): %v", tt.root, tt.chord, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GetChord(%s, %s) = %v, want %v", tt.root, tt.chord, got, tt.want)
+		}
+	}
+}
+
+func TestNotePosition(t *testing.T) {
+	tests := []struct {
+		note string
+		want int
+	}{
+		{"A", 0},
+		{"Bb", 1},
+		{"A#", 1},
+		{"Cx", 5},
+		{"C##", 5},
+		{"Ebb", 5},
+		{"Fb", 7},
+		{"E#", 8},
+		{"B#", 3},
+		{"Cbb", 1},
+		{"e♭", 6},
+		{"G#4", 11},
+	}
+	for _, tt := range tests {
+		got, err := NotePosition(tt.note)
+		if err != nil || got != tt.want {
+			t.Errorf("NotePosition(%s) = %d, %v, want %d", tt.note, got, err, tt.want)
+		}
+	}
+
+	for _, note := range []string{"", "H", "C###", "Cbbb", "C#b"} {
+		if _, err := NotePosition(note); err == nil {
+			t.Errorf("NotePosition(%s): expected an error", note)
+		}
+	}
+}
+
+func TestTransposeNote(t *testing.T) {
+	tests := []struct {
+		note  string
+		steps int
+		want  string
+	}{
+		{"Bb", 2, "C"},
+		{"Bb", -1, "A"},
+		{"Bb", 1, "B"},
+		{"Bb", 3, "Db"},
+		{"Cx", -2, "C"},
+		{"Ebb", -14, "C"},
+		{"Fb", -1, "Eb"},
+		{"E#", 1, "F#"},
+		{"C", -13, "B"},
+		{"F#", -6, "C"},
+		{"A", -1, "G#"},
+		{"A", 24, "A"},
+	}
+	for _, tt := range tests {
+		got, err := TransposeNote(tt.note, tt.steps)
+		if err != nil || got != tt.want {
+			t.Errorf("TransposeNote(%s, %d) = %s, %v, want %s",
+				tt.note, tt.steps, got, err, tt.want)
+		}
+	}
+}
+
+func TestGetNote(t *testing.T) {
+	tests := []struct {
+		note  string
+		steps int
+		want  string
+	}{
+		{"C", 1, "C#"},
+		{"C", -1, "B"},
+		{"A", -13, "G#"},
+		{"Bb", -24, "A#"},
+		{"Fb", 0, "E"},
+	}
+	for _, tt := range tests {
+		got, err := GetNote(tt.note, tt.steps)
+		if err != nil || got != tt.want {
+			t.Errorf("GetNote(%s, %d) = %s, %v, want %s",
+				tt.note, tt.steps, got, err, tt.want)
+		}
+	}
+}
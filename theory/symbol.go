@@ -0,0 +1,339 @@
+package theory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChordSymbol is a chord given in the standard notation, e.g. "F#m7b5",
+// "Bbmaj9", "C7#9", "D/F#" or "Gsus4add9"
+type ChordSymbol struct {
+	Symbol string
+	Root   string
+
+	// The triad: "" for major, "m", "dim", "aug", "halfdim", "sus2",
+	// "sus4" or "5" for a power chord
+	Quality string
+
+	// The sixth or the highest seventh, ninth, eleventh or thirteenth, e.g.
+	// "7", "maj9" or "6/9". Empty for triads.
+	Extension string
+
+	// The altered and added notes, e.g. "b5", "#9" or "add9"
+	Alterations []string
+
+	// The lowest note of a slash chord or empty
+	Bass string
+
+	// Distances of the chord notes from the root
+	Distances []int
+}
+
+// Suffix returns the symbol without the root and the bass, e.g. "m7b5"
+func (c ChordSymbol) Suffix() string {
+	alterations := strings.Join(c.Alterations, "")
+	if c.Quality == "halfdim" {
+		extension := c.Extension
+		if extension == "" {
+			extension = "7"
+		}
+		return "m" + extension + "b5" + alterations
+	}
+	return c.Quality + c.Extension + alterations
+}
+
+// Name returns the name of a known chord with the same notes or the suffix
+// of the symbol if there is none
+func (c ChordSymbol) Name() string {
+	names := make([]string, 0, len(Chords))
+	for name := range Chords {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if equalDistances(Chords[name], c.Distances) {
+			return name
+		}
+	}
+	return c.Suffix()
+}
+
+// Define returns the name of the chord that ChordName and GetChord know. A
+// chord that is not in Chords is named by the suffix of the symbol. It is
+// not added to Chords so it is not listed with them, e.g. in
+// GetChordsInScale.
+func (c ChordSymbol) Define() (string, error) {
+	name := c.Name()
+	if known, ok := ChordName(name); ok {
+		if !equalDistances(chordDistances(known), c.Distances) {
+			return "", fmt.Errorf("chord '%s' already exists with other notes", name)
+		}
+		return known, nil
+	}
+	symbolChords[name] = append([]int{}, c.Distances...)
+	return name, nil
+}
+
+func (c ChordSymbol) String() string {
+	ret := c.Root + c.Suffix()
+	if c.Bass != "" {
+		ret += "/" + c.Bass
+	}
+	return ret
+}
+
+func equalDistances(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// The spellings of the triad qualities, longest first
+var symbolQualities = []struct {
+	token   string
+	quality string
+}{
+	{"min", "m"}, {"mi", "m"}, {"m", "m"}, {"-", "m"},
+	{"dim", "dim"}, {"°", "dim"}, {"o", "dim"},
+	{"aug", "aug"}, {"+", "aug"},
+	{"ø", "halfdim"},
+	{"sus2", "sus2"}, {"sus4", "sus4"}, {"sus", "sus4"},
+}
+
+// The spellings of the extensions, longest first
+var symbolExtensions = []struct {
+	token     string
+	extension string
+}{
+	{"(maj7)", "maj7"},
+	{"maj13", "maj13"}, {"maj11", "maj11"}, {"maj9", "maj9"}, {"maj7", "maj7"},
+	{"M13", "maj13"}, {"M11", "maj11"}, {"M9", "maj9"}, {"M7", "maj7"},
+	{"Δ7", "maj7"}, {"Δ", "maj7"}, {"maj", ""}, {"M", ""},
+	{"6/9", "6/9"}, {"69", "6/9"},
+	{"13", "13"}, {"11", "11"}, {"9", "9"}, {"7", "7"}, {"6", "6"},
+}
+
+// The spellings of the alterations and the added notes, longest first
+var symbolAlterations = []struct {
+	token      string
+	alteration string
+}{
+	{"add13", "add13"}, {"add11", "add11"}, {"add9", "add9"},
+	{"add4", "add4"}, {"add2", "add2"},
+	{"sus2", "sus2"}, {"sus4", "sus4"}, {"sus", "sus4"},
+	{"b13", "b13"}, {"#11", "#11"}, {"+11", "#11"},
+	{"b9", "b9"}, {"#9", "#9"}, {"+9", "#9"},
+	{"b5", "b5"}, {"-5", "b5"}, {"#5", "#5"}, {"+5", "#5"},
+	{"♭13", "b13"}, {"♯11", "#11"}, {"♭9", "b9"}, {"♯9", "#9"},
+	{"♭5", "b5"}, {"♯5", "#5"},
+}
+
+// Distances of the triads
+var qualityDistances = map[string][]int{
+	"":        {0, 4, 7},
+	"m":       {0, 3, 7},
+	"dim":     {0, 3, 6},
+	"aug":     {0, 4, 8},
+	"halfdim": {0, 3, 6},
+	"sus2":    {0, 2, 7},
+	"sus4":    {0, 5, 7},
+	"5":       {0, 7},
+}
+
+// Split the root note from the start of the symbol. The root has an
+// uppercase letter and at most one accidental.
+func splitSymbolRoot(symbol string) (string, string, bool) {
+	if symbol == "" || !strings.ContainsRune("ABCDEFG", rune(symbol[0])) {
+		return "", symbol, false
+	}
+	for _, acc := range []string{"#", "b", "♯", "♭"} {
+		if strings.HasPrefix(symbol[1:], acc) {
+			n := 1 + len(acc)
+			return symbol[:n], symbol[n:], true
+		}
+	}
+	return symbol[:1], symbol[1:], true
+}
+
+// ParseChordSymbol parses a chord symbol to its root, quality, extension,
+// alterations and bass note and builds the chord from them. E.g.
+// "F#m7b5" is a minor seventh chord with a flat fifth and "D/F#" a D major
+// chord with F# in the bass. The root and the bass have an uppercase
+// letter.
+func ParseChordSymbol(symbol string) (ChordSymbol, error) {
+	ret := ChordSymbol{Symbol: symbol}
+	fail := func(format string, args ...interface{}) (ChordSymbol, error) {
+		return ChordSymbol{}, &InvalidChordSymbolError{
+			Symbol: symbol,
+			Reason: fmt.Sprintf(format, args...),
+		}
+	}
+
+	s := strings.TrimSpace(symbol)
+	root, rest, ok := splitSymbolRoot(s)
+	if !ok {
+		return fail("it must start with a root note from A to G")
+	}
+	ret.Root, _ = NormalizeNote(root)
+
+	// The bass note is after the last slash, unless it is the 9 of a 6/9
+	// chord
+	i := strings.LastIndex(rest, "/")
+	if i >= 0 && !(rest[i+1:] == "9" && strings.HasSuffix(rest[:i], "6")) {
+		bass, after, ok := splitSymbolRoot(rest[i+1:])
+		if !ok || after != "" {
+			return fail("invalid bass note '%s'", rest[i+1:])
+		}
+		ret.Bass, _ = NormalizeNote(bass)
+		rest = rest[:i]
+	}
+
+	if rest == "5" {
+		ret.Quality, rest = "5", ""
+	}
+	for _, q := range symbolQualities {
+		// "m" can't be the start of "maj"
+		if strings.HasPrefix(rest, q.token) && !strings.HasPrefix(rest, "maj") {
+			ret.Quality, rest = q.quality, rest[len(q.token):]
+			break
+		}
+	}
+	for _, e := range symbolExtensions {
+		if strings.HasPrefix(rest, e.token) {
+			ret.Extension, rest = e.extension, rest[len(e.token):]
+			break
+		}
+	}
+	for rest != "" {
+		rest = strings.TrimLeft(rest, "(),")
+		if rest == "" {
+			break
+		}
+		found := false
+		for _, a := range symbolAlterations {
+			if strings.HasPrefix(rest, a.token) {
+				ret.Alterations = append(ret.Alterations, a.alteration)
+				rest = rest[len(a.token):]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fail("unknown quality or alteration '%s'", rest)
+		}
+	}
+
+	distances, err := symbolDistances(ret)
+	if err != nil {
+		return fail("%v", err)
+	}
+	ret.Distances = distances
+	return ret, nil
+}
+
+// Build the distances of the chord notes from the parts of the symbol
+func symbolDistances(c ChordSymbol) ([]int, error) {
+	notes := map[int]bool{}
+	for _, d := range qualityDistances[c.Quality] {
+		notes[d] = true
+	}
+
+	seventh := 10
+	switch {
+	case strings.HasPrefix(c.Extension, "maj"):
+		seventh = 11
+	case c.Quality == "dim":
+		seventh = 9
+	}
+
+	switch c.Extension {
+	case "":
+		if c.Quality == "halfdim" {
+			notes[seventh] = true
+		}
+	case "6":
+		notes[9] = true
+	case "6/9":
+		notes[9] = true
+		notes[14] = true
+	case "7", "maj7":
+		notes[seventh] = true
+	case "9", "maj9":
+		notes[seventh] = true
+		notes[14] = true
+	case "11", "maj11":
+		notes[seventh] = true
+		notes[14] = true
+		notes[17] = true
+	case "13", "maj13":
+		notes[seventh] = true
+		notes[14] = true
+		notes[21] = true
+	}
+	if c.Quality == "5" && (c.Extension != "" || len(c.Alterations) > 0) {
+		return nil, fmt.Errorf("a power chord can't have other notes")
+	}
+
+	has := func(ds ...int) bool {
+		for _, d := range ds {
+			if notes[d] {
+				return true
+			}
+		}
+		return false
+	}
+	replace := func(from []int, to int) {
+		for _, d := range from {
+			delete(notes, d)
+		}
+		notes[to] = true
+	}
+
+	for _, a := range c.Alterations {
+		switch a {
+		case "b5":
+			replace([]int{7}, 6)
+		case "#5":
+			replace([]int{7}, 8)
+		case "b9":
+			replace([]int{14}, 13)
+		case "#9":
+			if has(3) && !has(4) {
+				return nil, fmt.Errorf("a minor chord can't have a #9")
+			}
+			replace([]int{14}, 15)
+		case "#11":
+			replace([]int{17}, 18)
+		case "b13":
+			replace([]int{21}, 20)
+		case "add2":
+			notes[2] = true
+		case "add4":
+			notes[5] = true
+		case "add9":
+			notes[14] = true
+		case "add11":
+			notes[17] = true
+		case "add13":
+			notes[21] = true
+		case "sus2":
+			replace([]int{3, 4}, 2)
+		case "sus4":
+			replace([]int{3, 4}, 5)
+		}
+	}
+
+	ret := make([]int, 0, len(notes))
+	for d := range notes {
+		ret = append(ret, d)
+	}
+	sort.Ints(ret)
+	return ret, nil
+}
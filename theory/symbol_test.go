@@ -0,0 +1,125 @@
+package theory
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseChordSymbol(t *testing.T) {
+	tests := []struct {
+		symbol    string
+		root      string
+		bass      string
+		distances []int
+		name      string
+		str       string
+	}{
+		{"F#m7b5", "F#", "", []int{0, 3, 6, 10}, "halfdim7", "F#m7b5"},
+		{"Cø", "C", "", []int{0, 3, 6, 10}, "halfdim7", "Cm7b5"},
+		{"Cø7", "C", "", []int{0, 3, 6, 10}, "halfdim7", "Cm7b5"},
+		{"Cø9", "C", "", []int{0, 3, 6, 10, 14}, "m9b5", "Cm9b5"},
+		{"Bbmaj9", "Bb", "", []int{0, 4, 7, 11, 14}, "maj9", "Bbmaj9"},
+		{"BbΔ7", "Bb", "", []int{0, 4, 7, 11}, "maj7", "Bbmaj7"},
+		{"C7#9", "C", "", []int{0, 4, 7, 10, 15}, "7#9", "C7#9"},
+		{"C6/9", "C", "", []int{0, 4, 7, 9, 14}, "6/9", "C6/9"},
+		{"C69", "C", "", []int{0, 4, 7, 9, 14}, "6/9", "C6/9"},
+		{"C6/9/E", "C", "E", []int{0, 4, 7, 9, 14}, "6/9", "C6/9/E"},
+		{"C6/E", "C", "E", []int{0, 4, 7, 9}, "6", "C6/E"},
+		{"Cm6/Eb", "C", "Eb", []int{0, 3, 7, 9}, "m6", "Cm6/Eb"},
+		{"D/F#", "D", "F#", []int{0, 4, 7}, "Major", "D/F#"},
+		{"Gsus4add9", "G", "", []int{0, 5, 7, 14}, "sus4add9", "Gsus4add9"},
+		{"Cm(maj7)", "C", "", []int{0, 3, 7, 11}, "minmaj7", "Cmmaj7"},
+		{"C°7", "C", "", []int{0, 3, 6, 9}, "dim7", "Cdim7"},
+		{"C+", "C", "", []int{0, 4, 8}, "Augmented", "Caug"},
+		{"C5", "C", "", []int{0, 7}, "Power", "C5"},
+		{"Ebm11", "Eb", "", []int{0, 3, 7, 10, 14, 17}, "m11", "Ebm11"},
+	}
+	for _, tt := range tests {
+		cs, err := ParseChordSymbol(tt.symbol)
+		if err != nil {
+			t.Errorf("ParseChordSymbol(%s): %v", tt.symbol, err)
+			continue
+		}
+		if cs.Root != tt.root || cs.Bass != tt.bass {
+			t.Errorf("ParseChordSymbol(%s) root %s bass %s, want %s and %s",
+				tt.symbol, cs.Root, cs.Bass, tt.root, tt.bass)
+		}
+		if !reflect.DeepEqual(cs.Distances, tt.distances) {
+			t.Errorf("ParseChordSymbol(%s) distances %v, want %v",
+				tt.symbol, cs.Distances, tt.distances)
+		}
+		if cs.Name() != tt.name {
+			t.Errorf("ParseChordSymbol(%s).Name() = %s, want %s", tt.symbol, cs.Name(), tt.name)
+		}
+		if cs.String() != tt.str {
+			t.Errorf("ParseChordSymbol(%s).String() = %s, want %s", tt.symbol, cs.String(), tt.str)
+		}
+
+		// The symbol without the root and the bass gives the same chord
+		again, err := ParseChordSymbol(tt.root + cs.Suffix())
+		if err != nil || !reflect.DeepEqual(again.Distances, cs.Distances) {
+			t.Errorf("ParseChordSymbol(%s%s) = %v, %v, want %v",
+				tt.root, cs.Suffix(), again.Distances, err, cs.Distances)
+		}
+	}
+}
+
+func TestParseChordSymbolInvalid(t *testing.T) {
+	tests := []string{
+		"", "H7", "c7", "C7/", "C/H", "C/E/G", "Cmaj7#", "Cfoo",
+		"Cm7#9", "C5b9",
+	}
+	for _, symbol := range tests {
+		_, err := ParseChordSymbol(symbol)
+		var se *InvalidChordSymbolError
+		if !errors.As(err, &se) {
+			t.Errorf("ParseChordSymbol(%s) = %v, want an InvalidChordSymbolError", symbol, err)
+		}
+	}
+}
+
+func TestChordSymbolDefine(t *testing.T) {
+	cs, err := ParseChordSymbol("Cm7b5add11")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, err := cs.Define()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "m7b5add11" {
+		t.Errorf("Define() = %s, want m7b5add11", name)
+	}
+	if _, ok := Chords[name]; ok {
+		t.Errorf("chord '%s' was added to Chords", name)
+	}
+
+	notes, err := GetChord("C", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"C", "Eb", "Gb", "Bb", "F"}
+	if !reflect.DeepEqual(notes, want) {
+		t.Errorf("GetChord(C, %s) = %v, want %v", name, notes, want)
+	}
+
+	chords, err := GetChordsInScale("C", "Locrian Mode")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range chords["C"] {
+		if c == name {
+			t.Errorf("chord '%s' is listed in the scale", name)
+		}
+	}
+
+	// A known chord keeps its name
+	cs, err = ParseChordSymbol("F#m7b5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, err := cs.Define(); err != nil || name != "halfdim7" {
+		t.Errorf("Define() = %s, %v, want halfdim7", name, err)
+	}
+}